@@ -0,0 +1,428 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// ExpiredAt is when the entry become stale
+	ExpiredAt time.Time
+	// StaleUntil is the last time the stale entry can be served while revalidating
+	StaleUntil time.Time
+}
+
+// CacheStore keep the cached response, implement this for other backend (e.g. redis)
+type CacheStore interface {
+	Get(ctx context.Context, key string) (entry *CacheEntry, found bool, err error)
+	Set(ctx context.Context, key string, entry CacheEntry) error
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+type CacheConfig struct {
+	// Name is the key namespace, used for invalidation
+	Name string
+	TTL  time.Duration
+	// StaleWhileRevalidate is how long stale entry can be served while it is refreshed in background
+	StaleWhileRevalidate time.Duration
+	// QueryParams is the query params used for cache key, nil means all query params
+	QueryParams []string
+	// Headers is the request headers used for cache key (e.g. Accept-Language)
+	Headers []string
+	// Private emit Cache-Control private instead of public
+	Private bool
+	// CacheAuthorized cache request with Authorization header, the Authorization is added to the cache key and
+	// Cache-Control is private. By default request with Authorization header is not cached
+	CacheAuthorized bool
+	// Store default is in-memory LRU store with 1000 entries
+	Store CacheStore
+}
+
+// NewCacheMiddleware create middleware that cache successful GET response,
+// response with Set-Cookie header is never cached and request with Authorization header is only cached with CacheAuthorized
+func NewCacheMiddleware(conf CacheConfig) func(http.Handler) http.Handler {
+	if conf.Store == nil {
+		conf.Store = NewLRUCacheStore(1000)
+	}
+
+	var mu sync.Mutex
+	revalidating := map[string]bool{}
+
+	return func(next http.Handler) http.Handler {
+		revalidate := func(key string, r *http.Request) {
+			mu.Lock()
+			if revalidating[key] {
+				mu.Unlock()
+				return
+			}
+			revalidating[key] = true
+			mu.Unlock()
+
+			r = r.Clone(detachedContext{r.Context()})
+			go func() {
+				defer func() {
+					mu.Lock()
+					delete(revalidating, key)
+					mu.Unlock()
+				}()
+
+				bw := &bufferWriter{header: make(http.Header)}
+				next.ServeHTTP(bw, r)
+				storeCacheEntry(conf, key, bw.StatusCode(), bw.header, bw.body.Bytes())
+			}()
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || (r.Header.Get("Authorization") != "" && !conf.CacheAuthorized) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := CacheKey(conf, r)
+			now := time.Now()
+
+			entry, found, err := conf.Store.Get(r.Context(), key)
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("error on get cache")
+			}
+
+			if found && now.Before(entry.ExpiredAt) {
+				writeCacheEntry(w, conf, entry, "HIT", entry.ExpiredAt.Sub(now))
+				return
+			}
+
+			if found && now.Before(entry.StaleUntil) {
+				revalidate(key, r)
+				writeCacheEntry(w, conf, entry, "STALE", 0)
+				return
+			}
+
+			before := w.Header().Clone()
+			cw := &cacheWriter{recordWriter: recordWriter{ResponseWriter: w}, cacheControl: cacheControl(conf, conf.TTL), vary: cacheVary(conf)}
+			next.ServeHTTP(cw, r)
+
+			storeCacheEntry(conf, key, cw.StatusCode(), changedHeader(before, w.Header()), cw.body.Bytes())
+		})
+	}
+}
+
+// CacheKey build cache key from method, path, selected query params and headers,
+// and hash of Authorization header when CacheAuthorized is set
+func CacheKey(conf CacheConfig, r *http.Request) string {
+	query := r.URL.Query()
+	if conf.QueryParams != nil {
+		selected := url.Values{}
+		for _, param := range conf.QueryParams {
+			if values, ok := query[param]; ok {
+				selected[param] = values
+			}
+		}
+		query = selected
+	}
+
+	var sb strings.Builder
+	sb.WriteString(cachePathPrefix(conf.Name, r.Method, r.URL.Path))
+	sb.WriteString(query.Encode())
+
+	headers := append([]string{}, conf.Headers...)
+	sort.Strings(headers)
+	for _, header := range headers {
+		sb.WriteString("|")
+		sb.WriteString(http.CanonicalHeaderKey(header))
+		sb.WriteString("=")
+		sb.WriteString(strings.Join(r.Header.Values(header), ","))
+	}
+
+	if auth := r.Header.Get("Authorization"); conf.CacheAuthorized && auth != "" {
+		hash := sha256.Sum256([]byte(auth))
+		sb.WriteString("|Authorization=")
+		sb.WriteString(hex.EncodeToString(hash[:]))
+	}
+
+	return sb.String()
+}
+
+// InvalidateCache remove all cached entries of the cache name
+func InvalidateCache(ctx context.Context, store CacheStore, name string) error {
+	return store.DeletePrefix(ctx, name+":")
+}
+
+// InvalidateCachePath remove all cached entries of the path regardless the query params and headers
+func InvalidateCachePath(ctx context.Context, store CacheStore, name string, path string) error {
+	return store.DeletePrefix(ctx, cachePathPrefix(name, http.MethodGet, path))
+}
+
+func cachePathPrefix(name string, method string, path string) string {
+	return fmt.Sprintf("%s:%s %s?", name, method, path)
+}
+
+func cacheControl(conf CacheConfig, maxAge time.Duration) string {
+	visibility := "public"
+	if conf.Private || conf.CacheAuthorized {
+		visibility = "private"
+	}
+
+	value := fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds()))
+	if conf.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(conf.StaleWhileRevalidate.Seconds()))
+	}
+
+	return value
+}
+
+// cacheVary return the request headers of the cache key, so shared cache downstream keep a variant per header value
+func cacheVary(conf CacheConfig) []string {
+	vary := []string{}
+	for _, header := range conf.Headers {
+		vary = append(vary, http.CanonicalHeaderKey(header))
+	}
+
+	if conf.CacheAuthorized {
+		vary = append(vary, "Authorization")
+	}
+
+	return vary
+}
+
+// addVary add the headers to Vary header that are not in it yet
+func addVary(header http.Header, vary []string) {
+	existing := map[string]bool{}
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			existing[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+
+	for _, name := range vary {
+		if !existing[name] {
+			header.Add("Vary", name)
+			existing[name] = true
+		}
+	}
+}
+
+func storeCacheEntry(conf CacheConfig, key string, statusCode int, header http.Header, body []byte) {
+	if statusCode != http.StatusOK || header.Get("Set-Cookie") != "" {
+		return
+	}
+
+	header.Del("X-Cache")
+	header.Del("Cache-Control")
+
+	now := time.Now()
+	err := conf.Store.Set(context.Background(), key, CacheEntry{
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       body,
+		ExpiredAt:  now.Add(conf.TTL),
+		StaleUntil: now.Add(conf.TTL + conf.StaleWhileRevalidate),
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("error on set cache")
+	}
+}
+
+func writeCacheEntry(w http.ResponseWriter, conf CacheConfig, entry *CacheEntry, status string, maxAge time.Duration) {
+	dst := w.Header()
+	for k, v := range entry.Header {
+		dst[k] = v
+	}
+	dst.Set("Cache-Control", cacheControl(conf, maxAge))
+	dst.Set("X-Cache", status)
+	addVary(dst, cacheVary(conf))
+
+	w.WriteHeader(entry.StatusCode)
+	w.Write(entry.Body)
+}
+
+// changedHeader return the header added or changed by the handler
+func changedHeader(before http.Header, after http.Header) http.Header {
+	header := make(http.Header)
+	for k, v := range after {
+		if strings.Join(before[k], "\n") != strings.Join(v, "\n") {
+			header[k] = append([]string{}, v...)
+		}
+	}
+
+	return header
+}
+
+// cacheWriter emit the cache headers only for successful response
+type cacheWriter struct {
+	recordWriter
+	cacheControl string
+	vary         []string
+}
+
+func (cw *cacheWriter) WriteHeader(code int) {
+	if cw.code == 0 && code == http.StatusOK {
+		cw.Header().Set("Cache-Control", cw.cacheControl)
+		cw.Header().Set("X-Cache", "MISS")
+		addVary(cw.Header(), cw.vary)
+	}
+	cw.recordWriter.WriteHeader(code)
+}
+
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.recordWriter.Write(p)
+}
+
+// detachedContext keep the request context values without its cancellation,
+// so background revalidation is not cancelled when the original request is done
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// bufferWriter keep the whole response in memory
+type bufferWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (bw *bufferWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferWriter) WriteHeader(code int) {
+	if bw.code == 0 {
+		bw.code = code
+	}
+}
+
+func (bw *bufferWriter) Write(p []byte) (int, error) {
+	if bw.code == 0 {
+		bw.code = http.StatusOK
+	}
+	return bw.body.Write(p)
+}
+
+func (bw *bufferWriter) StatusCode() int {
+	if bw.code == 0 {
+		return http.StatusOK
+	}
+	return bw.code
+}
+
+type lruCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+type lruCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUCacheStore create in-memory store that evict least recently used entry when capacity is reached
+func NewLRUCacheStore(capacity int) CacheStore {
+	return &lruCacheStore{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (s *lruCacheStore) Get(ctx context.Context, key string) (*CacheEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := elem.Value.(*lruCacheItem)
+	if time.Now().After(item.entry.StaleUntil) {
+		s.remove(elem)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(elem)
+	entry := item.entry
+	return &entry, true, nil
+}
+
+func (s *lruCacheStore) Set(ctx context.Context, key string, entry CacheEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		elem.Value.(*lruCacheItem).entry = entry
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&lruCacheItem{key: key, entry: entry})
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *lruCacheStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+
+	return nil
+}
+
+func (s *lruCacheStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, elem := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(elem)
+		}
+	}
+
+	return nil
+}
+
+func (s *lruCacheStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.items, elem.Value.(*lruCacheItem).key)
+}
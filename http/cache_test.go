@@ -0,0 +1,189 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	store := NewLRUCacheStore(10)
+	cache := NewCacheMiddleware(CacheConfig{
+		Name:        "provinces",
+		TTL:         time.Minute,
+		QueryParams: []string{"page"},
+		Store:       store,
+	})
+
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+
+	calls := 0
+	handler := cache(newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		calls++
+		if r.URL.Query().Get("page") == "error" {
+			response.Error = ErrUnknown
+			return
+		}
+		response.Data = calls
+		return
+	}))
+
+	request := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request("/provinces?page=1&ts=1")
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"), "Expect cache miss")
+	assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"), "Expect cache control header")
+
+	second := request("/provinces?ts=2&page=1")
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"), "Expect cache hit, ts is not part of the key")
+	assert.Equal(t, first.Body.String(), second.Body.String(), "Expect same body")
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"), "Expect stored header")
+	assert.Equal(t, 1, calls, "Expect handler called once")
+
+	request("/provinces?page=2")
+	assert.Equal(t, 2, calls, "Expect different page not cached")
+
+	errResp := request("/provinces?page=error")
+	request("/provinces?page=error")
+	assert.Equal(t, 4, calls, "Expect error response not cached")
+	assert.Equal(t, "", errResp.Header().Get("Cache-Control"), "Expect no cache control for error")
+
+	_ = InvalidateCachePath(context.Background(), store, "provinces", "/provinces")
+	request("/provinces?page=1")
+	assert.Equal(t, 5, calls, "Expect invalidated entry not served")
+}
+
+func TestCacheMiddlewareAuthorization(t *testing.T) {
+	for _, cacheAuthorized := range []bool{false, true} {
+		cache := NewCacheMiddleware(CacheConfig{Name: "profile", TTL: time.Minute, CacheAuthorized: cacheAuthorized})
+
+		calls := 0
+		handler := cache(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Write([]byte(r.Header.Get("Authorization")))
+		}))
+
+		request := func(auth string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+			req.Header.Set("Authorization", auth)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			return w
+		}
+
+		request("Bearer user-1")
+		second := request("Bearer user-1")
+		other := request("Bearer user-2")
+
+		assert.Equal(t, "Bearer user-2", other.Body.String(), "Expect other user never get the cached response")
+		if !cacheAuthorized {
+			assert.Equal(t, 3, calls, "Expect authorized request not cached by default")
+			assert.Equal(t, "", second.Header().Get("X-Cache"), "Expect no cache header")
+			continue
+		}
+
+		assert.Equal(t, 2, calls, "Expect authorized request cached per Authorization")
+		assert.Equal(t, "HIT", second.Header().Get("X-Cache"), "Expect cache hit of the same user")
+		assert.True(t, strings.HasPrefix(second.Header().Get("Cache-Control"), "private, "), "Expect private cache control")
+		assert.Equal(t, []string{"Authorization"}, second.Header().Values("Vary"), "Expect vary on Authorization")
+	}
+}
+
+func TestCacheMiddlewareVary(t *testing.T) {
+	cache := NewCacheMiddleware(CacheConfig{
+		Name:                 "greeting",
+		TTL:                  time.Millisecond,
+		StaleWhileRevalidate: time.Minute,
+		Headers:              []string{"accept-language"},
+	})
+
+	handler := cache(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept-Encoding")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		req.Header.Set("Accept-Language", "id")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, status := range []string{"MISS", "STALE"} {
+		if status == "STALE" {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		w := request()
+		assert.Equal(t, status, w.Header().Get("X-Cache"), "Expect cache status")
+		assert.Equal(t, []string{"Accept-Encoding", "Accept-Language"}, w.Header().Values("Vary"), "Expect vary on %s", status)
+	}
+}
+
+func TestCacheMiddlewareStaleWhileRevalidate(t *testing.T) {
+	store := NewLRUCacheStore(10)
+	cache := NewCacheMiddleware(CacheConfig{
+		Name:                 "cities",
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		Store:                store,
+	})
+
+	handler := cache(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/cities", nil)
+	key := CacheKey(CacheConfig{Name: "cities"}, req)
+	_ = store.Set(context.Background(), key, CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       []byte("stale"),
+		ExpiredAt:  time.Now().Add(-time.Second),
+		StaleUntil: time.Now().Add(time.Minute),
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, "STALE", w.Header().Get("X-Cache"), "Expect stale response")
+	assert.Equal(t, "stale", w.Body.String(), "Expect stale body")
+	assert.Equal(t, "public, max-age=0, stale-while-revalidate=60", w.Header().Get("Cache-Control"), "Expect cache control header")
+
+	assert.Eventually(t, func() bool {
+		entry, found, _ := store.Get(context.Background(), key)
+		return found && string(entry.Body) == "data"
+	}, time.Second, 10*time.Millisecond, "Expect entry revalidated in background")
+}
+
+func TestLRUCacheStoreEviction(t *testing.T) {
+	store := NewLRUCacheStore(2)
+	ctx := context.Background()
+	entry := CacheEntry{StatusCode: http.StatusOK, StaleUntil: time.Now().Add(time.Minute)}
+
+	_ = store.Set(ctx, "a", entry)
+	_ = store.Set(ctx, "b", entry)
+	_, _, _ = store.Get(ctx, "a")
+	_ = store.Set(ctx, "c", entry)
+
+	_, found, _ := store.Get(ctx, "b")
+	assert.Equal(t, false, found, "Expect least recently used entry evicted")
+	_, found, _ = store.Get(ctx, "a")
+	assert.Equal(t, true, found, "Expect recently used entry kept")
+
+	_ = InvalidateCache(ctx, store, "x")
+	_ = store.DeletePrefix(ctx, "")
+	_, found, _ = store.Get(ctx, "c")
+	assert.Equal(t, false, found, "Expect all entries deleted")
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins accepts exact origins ("https://app.example.com"), wildcard subdomains
+	// ("https://*.example.com") or "*" to allow any origin, "*" can not be used with AllowCredentials
+	AllowedOrigins []string
+	// AllowedMethods defaults to GET, POST, PUT, PATCH, DELETE and HEAD when empty
+	AllowedMethods []string
+	// AllowedHeaders defaults to Accept, Authorization, Content-Type and X-Requested-With when empty,
+	// use "*" to allow any header
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long (in seconds) the preflight result can be cached, 0 means not set
+	MaxAge int
+}
+
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+}
+
+var defaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"}
+
+type cors struct {
+	CustomWriterV2
+	conf           CORSConfig
+	allowAll       bool
+	allowAllHeader bool
+	origins        []string
+	wildcards      [][2]string
+	methods        []string
+	headers        []string
+}
+
+// NewCORSMiddleware create middleware that handle CORS and preflight request,
+// disallowed origin will be rejected with ErrOriginNotAllowed in V2 envelope.
+// It panics when "*" origin is used with AllowCredentials, since it would give every site credentialed access
+func NewCORSMiddleware(c HandlerContextV2, conf CORSConfig) func(http.Handler) http.Handler {
+	m := &cors{CustomWriterV2: CustomWriterV2{C: c}, conf: conf}
+
+	for _, origin := range conf.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			if conf.AllowCredentials {
+				panic("cors: \"*\" origin can not be used with AllowCredentials")
+			}
+			m.allowAll = true
+		} else if i := strings.Index(origin, "*"); i >= 0 {
+			m.wildcards = append(m.wildcards, [2]string{origin[:i], origin[i+1:]})
+		} else {
+			m.origins = append(m.origins, origin)
+		}
+	}
+
+	methods := conf.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	for _, method := range methods {
+		m.methods = append(m.methods, strings.ToUpper(method))
+	}
+
+	headers := conf.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	for _, header := range headers {
+		if header == "*" {
+			m.allowAllHeader = true
+			continue
+		}
+		m.headers = append(m.headers, http.CanonicalHeaderKey(header))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.serve(w, r, next)
+		})
+	}
+}
+
+func (m *cors) serve(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	origin := r.Header.Get("Origin")
+	w.Header().Add("Vary", "Origin")
+
+	if origin == "" {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	if !m.isOriginAllowed(origin) {
+		m.WriteError(w, ErrOriginNotAllowed, nil)
+		return
+	}
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		m.preflight(w, r, origin)
+		return
+	}
+
+	m.setOriginHeaders(w, origin)
+	if len(m.conf.ExposedHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(m.conf.ExposedHeaders, ", "))
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+func (m *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	if !m.isMethodAllowed(method) {
+		m.WriteError(w, ErrCORSRequestNotAllowed, nil)
+		return
+	}
+
+	reqHeaders := parseHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+	if !m.areHeadersAllowed(reqHeaders) {
+		m.WriteError(w, ErrCORSRequestNotAllowed, nil)
+		return
+	}
+
+	m.setOriginHeaders(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(m.methods, ", "))
+	if len(reqHeaders) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(reqHeaders, ", "))
+	}
+	if m.conf.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(m.conf.MaxAge))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *cors) setOriginHeaders(w http.ResponseWriter, origin string) {
+	if m.allowAll {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if m.conf.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (m *cors) isOriginAllowed(origin string) bool {
+	if m.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	for _, o := range m.origins {
+		if o == origin {
+			return true
+		}
+	}
+
+	for _, w := range m.wildcards {
+		if len(origin) <= len(w[0])+len(w[1]) || !strings.HasPrefix(origin, w[0]) || !strings.HasSuffix(origin, w[1]) {
+			continue
+		}
+
+		// the wildcard part must only be subdomain labels
+		if sub := origin[len(w[0]) : len(origin)-len(w[1])]; !strings.ContainsAny(sub, "/:@?#") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *cors) isMethodAllowed(method string) bool {
+	if method == http.MethodOptions {
+		return true
+	}
+
+	for _, allowed := range m.methods {
+		if allowed == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *cors) areHeadersAllowed(headers []string) bool {
+	if m.allowAllHeader {
+		return true
+	}
+
+	for _, header := range headers {
+		found := false
+		for _, allowed := range m.headers {
+			if allowed == header {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseHeaderList(value string) []string {
+	headers := []string{}
+	for _, header := range strings.Split(value, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+
+	return headers
+}
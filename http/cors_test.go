@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSTestHandler(conf CORSConfig) http.Handler {
+	cors := NewCORSMiddleware(NewContextHandlerV2(false), conf)
+	return cors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	handler := newCORSTestHandler(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+		ExposedHeaders:   []string{"X-Request-Id"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"), "Expect origin echoed")
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), "Expect credentials allowed")
+	assert.Equal(t, "X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"), "Expect exposed headers")
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "https://admin.example.org")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, "https://admin.example.org", w.Header().Get("Access-Control-Allow-Origin"), "Expect wildcard subdomain allowed")
+}
+
+func TestCORSRejectedOrigin(t *testing.T) {
+	handler := newCORSTestHandler(CORSConfig{
+		AllowedOrigins: []string{"https://*.example.org"},
+	})
+
+	for _, origin := range []string{"https://evil.com", "https://example.org", "https://evil.com/.example.org"} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		respJson := &ResponseV2{}
+		_ = json.Unmarshal(body, respJson)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "Expect 400 status code")
+		assert.Equal(t, http.StatusForbidden, respJson.StatusCode, "Expect 403 status code in body")
+		assert.Equal(t, false, respJson.Success, "Expect Success False")
+		assert.Equal(t, []string{ErrOriginNotAllowed.ResponseDesc}, respJson.Message, "Expect origin error message")
+		assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Origin"), "Expect no allow origin header")
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := newCORSTestHandler(CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"get", "post"},
+		MaxAge:         600,
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "content-type, authorization")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code, "Expect 204 status code")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"), "Expect any origin")
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"), "Expect allowed methods")
+	assert.Equal(t, "Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"), "Expect allowed headers")
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"), "Expect max age")
+
+	req = httptest.NewRequest(http.MethodOptions, "/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(body, respJson)
+
+	assert.Equal(t, http.StatusForbidden, respJson.StatusCode, "Expect 403 status code in body")
+	assert.Equal(t, []string{ErrCORSRequestNotAllowed.ResponseDesc}, respJson.Message, "Expect CORS error message")
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	assert.Panics(t, func() {
+		NewCORSMiddleware(HandlerContextV2{}, CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	}, "Expect \"*\" origin with credentials is rejected")
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// CursorCodec encode the last sort key values into opaque cursor signed with HMAC-SHA256,
+// so client can not forge cursor to scan with arbitrary key
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec create codec with the HMAC secret, it panics when the secret is empty because the cursor could be forged
+func NewCursorCodec(secret []byte) CursorCodec {
+	if len(secret) == 0 {
+		panic("cursor: secret can not be empty")
+	}
+
+	return CursorCodec{secret: secret}
+}
+
+// Encode create cursor from the sort key values of a row, e.g. codec.Encode(row.CreatedAt, row.ID)
+func (c CursorCodec) Encode(values ...interface{}) (string, error) {
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verify the cursor and decode the values into dest in the same order as Encode,
+// ErrInvalidCursor is returned when the cursor is malformed or the signature does not match
+func (c CursorCodec) Decode(cursor string, dest ...interface{}) error {
+	encodedPayload, encodedSignature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+
+	values := []json.RawMessage{}
+	if err := json.Unmarshal(payload, &values); err != nil || len(values) != len(dest) {
+		return ErrInvalidCursor
+	}
+
+	for i, value := range values {
+		if err := json.Unmarshal(value, dest[i]); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+
+	return nil
+}
+
+func (c CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// TrimCursorRows trim the extra row of keyset query fetched with Limit+1 to detect more data,
+// rows fetched with Before cursor are queried in reverse order so they are reversed back
+func TrimCursorRows[T any](reqPage RequestPagination, rows []T) ([]T, bool) {
+	hasMore := len(rows) > reqPage.Limit
+	if hasMore {
+		rows = rows[:reqPage.Limit]
+	}
+
+	if reqPage.Before != "" {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	return rows, hasMore
+}
+
+// GetCursorPagination create keyset pagination from the cursor of the first and last row of the page,
+// hasMore is whether there is more data in the direction of the request (after by default, or before)
+func GetCursorPagination(reqPage RequestPagination, hasMore bool, firstCursor string, lastCursor string) Pagination {
+	pagination := Pagination{
+		PageSize: reqPage.Limit,
+		HasMore:  hasMore,
+	}
+
+	if reqPage.Before != "" {
+		pagination.NextCursor = lastCursor
+		if hasMore {
+			pagination.PrevCursor = firstCursor
+		}
+	} else {
+		if hasMore {
+			pagination.NextCursor = lastCursor
+		}
+		if reqPage.After != "" {
+			pagination.PrevCursor = firstCursor
+		}
+	}
+	pagination.HasNext = pagination.NextCursor != ""
+	pagination.HasPrev = pagination.PrevCursor != ""
+
+	return pagination
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cursor, err := codec.Encode(createdAt, int64(42))
+	assert.Nil(t, err, "Expect no error")
+
+	var decodedAt time.Time
+	var id int64
+	assert.Nil(t, codec.Decode(cursor, &decodedAt, &id), "Expect valid cursor")
+	assert.True(t, createdAt.Equal(decodedAt), "Expect created at")
+	assert.Equal(t, int64(42), id, "Expect id")
+
+	tests := []struct {
+		name   string
+		cursor string
+		dest   []interface{}
+	}{
+		{name: "empty", cursor: "", dest: []interface{}{&id}},
+		{name: "not base64", cursor: "!!!.???", dest: []interface{}{&id}},
+		{name: "tampered payload", cursor: "WzQzXQ" + cursor[strings.Index(cursor, "."):], dest: []interface{}{&id}},
+		{name: "signed with other secret", cursor: mustEncode(NewCursorCodec([]byte("other")), 42), dest: []interface{}{&id}},
+		{name: "wrong value count", cursor: cursor, dest: []interface{}{&id}},
+		{name: "wrong value type", cursor: mustEncode(codec, "abc"), dest: []interface{}{&id}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ErrInvalidCursor, codec.Decode(tt.cursor, tt.dest...), "Expect ErrInvalidCursor")
+		})
+	}
+}
+
+func TestNewCursorCodecEmptySecret(t *testing.T) {
+	assert.Panics(t, func() { NewCursorCodec(nil) }, "Expect nil secret is rejected")
+	assert.Panics(t, func() { NewCursorCodec([]byte{}) }, "Expect empty secret is rejected")
+}
+
+func mustEncode(codec CursorCodec, values ...interface{}) string {
+	cursor, _ := codec.Encode(values...)
+	return cursor
+}
+
+func TestCursorPagination(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?limit=2&after=a", nil)
+	reqPage := GetPagination(req, 10, PaginationFields{LimitField: "limit", AfterField: "after", BeforeField: "before"})
+	assert.Equal(t, "a", reqPage.After, "Expect after cursor")
+	assert.Equal(t, "", reqPage.Before, "Expect no before cursor")
+
+	tests := []struct {
+		name       string
+		reqPage    RequestPagination
+		rows       []int
+		expected   []int
+		pagination Pagination
+	}{
+		{
+			name:       "first page",
+			reqPage:    RequestPagination{Limit: 2},
+			rows:       []int{1, 2, 3},
+			expected:   []int{1, 2},
+			pagination: Pagination{PageSize: 2, NextCursor: "2", HasMore: true, HasNext: true},
+		},
+		{
+			name:       "middle page",
+			reqPage:    RequestPagination{Limit: 2, After: "2"},
+			rows:       []int{3, 4, 5},
+			expected:   []int{3, 4},
+			pagination: Pagination{PageSize: 2, NextCursor: "4", PrevCursor: "3", HasMore: true, HasNext: true, HasPrev: true},
+		},
+		{
+			name:       "last page",
+			reqPage:    RequestPagination{Limit: 2, After: "4"},
+			rows:       []int{5},
+			expected:   []int{5},
+			pagination: Pagination{PageSize: 2, PrevCursor: "5", HasPrev: true},
+		},
+		{
+			name:       "before cursor",
+			reqPage:    RequestPagination{Limit: 2, Before: "5"},
+			rows:       []int{4, 3, 2},
+			expected:   []int{3, 4},
+			pagination: Pagination{PageSize: 2, NextCursor: "4", PrevCursor: "3", HasMore: true, HasNext: true, HasPrev: true},
+		},
+		{
+			name:       "before cursor reach first row",
+			reqPage:    RequestPagination{Limit: 2, Before: "3"},
+			rows:       []int{2, 1},
+			expected:   []int{1, 2},
+			pagination: Pagination{PageSize: 2, NextCursor: "2", HasNext: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, hasMore := TrimCursorRows(tt.reqPage, tt.rows)
+			assert.Equal(t, tt.expected, rows, "Expect trimmed rows")
+
+			pagination := GetCursorPagination(tt.reqPage, hasMore, itoa(rows[0]), itoa(rows[len(rows)-1]))
+			assert.Equal(t, tt.pagination, pagination, "Expect cursor pagination")
+		})
+	}
+}
+
+func itoa(i int) string {
+	b, _ := json.Marshal(i)
+	return string(b)
+}
+
+func TestCursorPaginationEnvelope(t *testing.T) {
+	pagination := &Pagination{PageSize: 2, NextCursor: "next", HasMore: true}
+
+	handlerV1 := NewHttpHandler(NewContextHandler(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		return HttpHandleResult{Data: []int{1, 2}, Pagination: pagination}
+	})
+	handlerV2 := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		return HttpHandleResultV2{Data: []int{1, 2}, Pagination: pagination}
+	})
+
+	w := httptest.NewRecorder()
+	handlerV1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	respV1 := SuccessResponse{}
+	json.Unmarshal(w.Body.Bytes(), &respV1)
+	assert.Equal(t, "next", respV1.NextCursor, "Expect V1 next cursor")
+	assert.True(t, respV1.HasMore, "Expect V1 has more")
+	assert.NotContains(t, w.Body.String(), "prev_cursor", "Expect empty prev cursor is omitted")
+
+	w = httptest.NewRecorder()
+	handlerV2.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	respV2 := struct {
+		Data SuccessResponseV2 `json:"data"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &respV2)
+	assert.Equal(t, "next", respV2.Data.NextCursor, "Expect V2 next cursor")
+	assert.True(t, respV2.Data.HasMore, "Expect V2 has more")
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterInt
+	FilterFloat
+	FilterBool
+	// FilterTime accept RFC3339 or 2006-01-02 value
+	FilterTime
+)
+
+func (t FilterType) String() string {
+	switch t {
+	case FilterInt:
+		return "integer"
+	case FilterFloat:
+		return "number"
+	case FilterBool:
+		return "boolean"
+	case FilterTime:
+		return "time"
+	default:
+		return "string"
+	}
+}
+
+type FilterOperator string
+
+const (
+	FilterEq      FilterOperator = "eq"
+	FilterNe      FilterOperator = "ne"
+	FilterGt      FilterOperator = "gt"
+	FilterGte     FilterOperator = "gte"
+	FilterLt      FilterOperator = "lt"
+	FilterLte     FilterOperator = "lte"
+	FilterLike    FilterOperator = "like"
+	FilterIn      FilterOperator = "in"
+	FilterNotIn   FilterOperator = "nin"
+	FilterBetween FilterOperator = "between"
+)
+
+var filterOperators = map[FilterOperator]bool{
+	FilterEq: true, FilterNe: true, FilterGt: true, FilterGte: true, FilterLt: true,
+	FilterLte: true, FilterLike: true, FilterIn: true, FilterNotIn: true, FilterBetween: true,
+}
+
+// defaultFilterOperators is the allowed operators when FilterField.Operators is empty
+var defaultFilterOperators = map[FilterType][]FilterOperator{
+	FilterString: {FilterEq, FilterNe, FilterIn, FilterNotIn, FilterLike},
+	FilterInt:    {FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn, FilterNotIn, FilterBetween},
+	FilterFloat:  {FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterBetween},
+	FilterBool:   {FilterEq, FilterNe},
+	FilterTime:   {FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterBetween},
+}
+
+// FilterField declare the type and allowed operators of filterable query field
+type FilterField struct {
+	Type FilterType
+	// Column is the column name used in Filter, default is the query field name
+	Column string
+	// Operators default is all operators that make sense for the type
+	Operators []FilterOperator
+}
+
+// FilterSchema map the query field name to its declaration, query field that is not in schema is ignored
+type FilterSchema map[string]FilterField
+
+// Filter is validated filter condition, filters are combined with AND.
+// Values are typed by FilterField.Type (string, int64, float64, bool or time.Time),
+// in/nin have one or more values, between has two values and other operators have one value
+type Filter struct {
+	Field    string
+	Operator FilterOperator
+	Values   []interface{}
+}
+
+// ParseFilters parse filters like status=eq:active&amount=gte:1000&created_at=between:2024-01-01,2024-02-01&tag=in:a,b,
+// value without operator is eq. Invalid filter return 400 error response with the reason
+func ParseFilters(query url.Values, schema FilterSchema) ([]Filter, error) {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filters := []Filter{}
+	for _, name := range names {
+		field := schema[name]
+		for _, value := range query[name] {
+			filter, err := parseFilter(name, field, value)
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+// GetFilters parse the filters of the request query
+func GetFilters(r *http.Request, schema FilterSchema) ([]Filter, error) {
+	return ParseFilters(r.URL.Query(), schema)
+}
+
+func parseFilter(name string, field FilterField, value string) (Filter, error) {
+	operator := FilterEq
+	if prefix, rest, ok := strings.Cut(value, ":"); ok && filterOperators[FilterOperator(prefix)] {
+		operator = FilterOperator(prefix)
+		value = rest
+	}
+
+	allowed := field.Operators
+	if len(allowed) == 0 {
+		allowed = defaultFilterOperators[field.Type]
+	}
+
+	if !containsFilterOperator(allowed, operator) {
+		return Filter{}, invalidFilterError(name, fmt.Sprintf("operator %s is not allowed, allowed operators: %s", operator, joinFilterOperators(allowed)))
+	}
+
+	rawValues := []string{value}
+	switch operator {
+	case FilterIn, FilterNotIn:
+		rawValues = strings.Split(value, ",")
+	case FilterBetween:
+		rawValues = strings.Split(value, ",")
+		if len(rawValues) != 2 {
+			return Filter{}, invalidFilterError(name, "between need 2 values separated by comma")
+		}
+	}
+
+	column := field.Column
+	if column == "" {
+		column = name
+	}
+
+	filter := Filter{Field: column, Operator: operator, Values: make([]interface{}, len(rawValues))}
+	for i, raw := range rawValues {
+		typed, err := parseFilterValue(field.Type, strings.TrimSpace(raw))
+		if err != nil {
+			return Filter{}, invalidFilterError(name, fmt.Sprintf("value %q is not valid %s", raw, field.Type))
+		}
+
+		filter.Values[i] = typed
+	}
+
+	return filter, nil
+}
+
+func parseFilterValue(filterType FilterType, value string) (interface{}, error) {
+	switch filterType {
+	case FilterInt:
+		return strconv.ParseInt(value, 10, 64)
+	case FilterFloat:
+		return strconv.ParseFloat(value, 64)
+	case FilterBool:
+		return strconv.ParseBool(value)
+	case FilterTime:
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02", value)
+	default:
+		return value, nil
+	}
+}
+
+func containsFilterOperator(operators []FilterOperator, operator FilterOperator) bool {
+	for _, op := range operators {
+		if op == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinFilterOperators(operators []FilterOperator) string {
+	names := make([]string, len(operators))
+	for i, op := range operators {
+		names[i] = string(op)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// invalidFilterError wrap ErrInvalidFilter with the reason, so errors.Is(err, ErrInvalidFilter) is true
+func invalidFilterError(name string, reason string) error {
+	return fmt.Errorf("%w %s: %s", ErrInvalidFilter, name, reason)
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFilterSchema = FilterSchema{
+	"status":     {Type: FilterString, Column: "o.status", Operators: []FilterOperator{FilterEq, FilterIn}},
+	"amount":     {Type: FilterInt},
+	"price":      {Type: FilterFloat},
+	"paid":       {Type: FilterBool},
+	"created_at": {Type: FilterTime},
+	"tag":        {Type: FilterString},
+}
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []Filter
+		err      string
+	}{
+		{name: "empty", query: "", expected: []Filter{}},
+		{name: "field not in schema is ignored", query: "page=2&sort=name", expected: []Filter{}},
+		{name: "eq", query: "status=eq:active", expected: []Filter{{Field: "o.status", Operator: FilterEq, Values: []interface{}{"active"}}}},
+		{name: "default eq", query: "status=active", expected: []Filter{{Field: "o.status", Operator: FilterEq, Values: []interface{}{"active"}}}},
+		{name: "value with colon", query: "tag=eq:a:b", expected: []Filter{{Field: "tag", Operator: FilterEq, Values: []interface{}{"a:b"}}}},
+		{name: "in", query: "tag=in:a,b", expected: []Filter{{Field: "tag", Operator: FilterIn, Values: []interface{}{"a", "b"}}}},
+		{name: "like", query: "tag=like:new", expected: []Filter{{Field: "tag", Operator: FilterLike, Values: []interface{}{"new"}}}},
+		{name: "int range", query: "amount=gte:1000&amount=lt:5000", expected: []Filter{
+			{Field: "amount", Operator: FilterGte, Values: []interface{}{int64(1000)}},
+			{Field: "amount", Operator: FilterLt, Values: []interface{}{int64(5000)}},
+		}},
+		{name: "float", query: "price=gt:9.5", expected: []Filter{{Field: "price", Operator: FilterGt, Values: []interface{}{9.5}}}},
+		{name: "bool", query: "paid=true", expected: []Filter{{Field: "paid", Operator: FilterEq, Values: []interface{}{true}}}},
+		{name: "between date", query: "created_at=between:2024-01-01,2024-02-01", expected: []Filter{{
+			Field:    "created_at",
+			Operator: FilterBetween,
+			Values:   []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		}}},
+		{name: "time", query: "created_at=gte:" + url.QueryEscape("2024-01-01T10:00:00Z"), expected: []Filter{{
+			Field:    "created_at",
+			Operator: FilterGte,
+			Values:   []interface{}{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		}}},
+		{name: "operator not allowed", query: "status=ne:active", err: "Invalid filter status: operator ne is not allowed, allowed operators: eq, in"},
+		{name: "default operator not allowed", query: "paid=gt:true", err: "Invalid filter paid: operator gt is not allowed, allowed operators: eq, ne"},
+		{name: "invalid int", query: "amount=gte:abc", err: `Invalid filter amount: value "abc" is not valid integer`},
+		{name: "invalid time", query: "created_at=gte:yesterday", err: `Invalid filter created_at: value "yesterday" is not valid time`},
+		{name: "invalid between", query: "amount=between:1", err: "Invalid filter amount: between need 2 values separated by comma"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			filters, err := ParseFilters(query, testFilterSchema)
+			if tt.err != "" {
+				if assert.NotNil(t, err, "Expect error") {
+					assert.True(t, errors.Is(err, ErrInvalidFilter), "Expect ErrInvalidFilter")
+					assert.Equal(t, tt.err, err.Error(), "Expect error message")
+				}
+				return
+			}
+
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, tt.expected, filters, "Expect filters")
+		})
+	}
+}
+
+func TestInvalidFilterResponse(t *testing.T) {
+	handler := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		filters, err := GetFilters(r, testFilterSchema)
+		if err != nil {
+			assert.True(t, errors.Is(err, ErrInvalidFilter), "Expect ErrInvalidFilter")
+			return ErrorResultV2(err)
+		}
+
+		return HttpHandleResultV2{Data: filters}
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?amount=gte:abc", nil))
+
+	resp := ResponseV2{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expect 400 status code")
+	assert.Equal(t, ErrInvalidFilter.HttpStatus, resp.StatusCode, "Expect ErrInvalidFilter status in body")
+	assert.Equal(t, []string{`Invalid filter amount: value "abc" is not valid integer`}, resp.Message, "Expect reason in message")
+}
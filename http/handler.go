@@ -1,8 +1,6 @@
 package http
 
 import (
-	"bytes"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/rs/zerolog/log"
@@ -33,26 +31,34 @@ func (h HttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	result := h.H(w, r)
 
 	if h.IsDebug {
-		// Read the content
-		var bodyBytes []byte
-		bodyBytes, _ = ioutil.ReadAll(r.Body)
-		r.Body.Close() //  must close
-		// Restore the io.ReadCloser to its original state
-		r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-		// Use the content
-		bodyString := string(bodyBytes)
-		log.Logger.Info().Msgf("[DEBUG] Request: %v", bodyString)
+		logDebugRequest(r)
 	}
 
+	writeResultHeaders(w, result.Headers, result.Cookies, result.InsecureCookies)
+
 	if result.Error != nil {
 		log.Logger.Error().Err(result.Error).Msgf("Response: %+v", result.Data)
+		setRequestCode(r, h.errorResponse(result.Error).HttpStatus)
 		h.WriteError(w, result.Error)
 		return
 	}
 
+	if result.RedirectURL != "" {
+		code := redirectStatus(result.StatusCode)
+		setRequestCode(r, code)
+		http.Redirect(w, r, result.RedirectURL, code)
+		return
+	}
+
+	if result.StatusCode == 0 {
+		setRequestCode(r, http.StatusOK)
+	} else {
+		setRequestCode(r, result.StatusCode)
+	}
+
 	if result.IsPlainResponse {
 		h.WritePlain(w, result.Data, result.StatusCode)
 	} else {
-		h.Write(w, result.Data, result.StatusCode, result.Pagination)
+		h.Write(w, result.Data, result.StatusCode, writePaginationLinks(w, r, result.Pagination, h.C.PaginationLinks))
 	}
 }
@@ -0,0 +1,158 @@
+package handlertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// updateGoldenEnv rewrite golden files instead of comparing when it is true, e.g. `UPDATE_GOLDEN=1 go test ./...`.
+// It is environment variable instead of flag, so packages that do not use golden files accept the same command
+const updateGoldenEnv = "UPDATE_GOLDEN"
+
+const maskedValue = "<masked>"
+
+type golden struct {
+	dir   string
+	paths [][]string
+	keys  map[string]bool
+}
+
+type GoldenOption func(*golden)
+
+// GoldenDir set the directory of golden files, default is testdata
+func GoldenDir(dir string) GoldenOption {
+	return func(g *golden) {
+		g.dir = dir
+	}
+}
+
+// MaskFields replace the value on the paths with "<masked>", path is dot separated field name
+// from the envelope root and * match any field or array index (e.g. data.*.created_at)
+func MaskFields(paths ...string) GoldenOption {
+	return func(g *golden) {
+		for _, path := range paths {
+			g.paths = append(g.paths, strings.Split(path, "."))
+		}
+	}
+}
+
+// MaskKeys replace the value of the fields with the names anywhere in the envelope with "<masked>"
+func MaskKeys(keys ...string) GoldenOption {
+	return func(g *golden) {
+		for _, key := range keys {
+			g.keys[key] = true
+		}
+	}
+}
+
+type goldenSnapshot struct {
+	HttpStatus int         `json:"http_status"`
+	Body       interface{} `json:"body"`
+}
+
+// AssertGolden compare the http status and serialized envelope with the golden file <dir>/<name>.golden.json,
+// golden file is written when the test run with UPDATE_GOLDEN=1
+func (res *Response) AssertGolden(name string, opts ...GoldenOption) *Response {
+	res.t.Helper()
+
+	g := &golden{dir: "testdata", keys: map[string]bool{}}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(res.Body))
+	decoder.UseNumber()
+
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		res.t.Fatalf("failed to decode response body: %v, body: %s", err, res.Body)
+		return res
+	}
+
+	for _, path := range g.paths {
+		body = maskPath(body, path)
+	}
+	if len(g.keys) > 0 {
+		body = maskKeys(body, g.keys)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(goldenSnapshot{HttpStatus: res.Recorder.Code, Body: body}); err != nil {
+		res.t.Fatalf("failed to encode snapshot: %v", err)
+		return res
+	}
+	actual := buf.Bytes()
+
+	file := filepath.Join(g.dir, name+".golden.json")
+	if update, _ := strconv.ParseBool(os.Getenv(updateGoldenEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			res.t.Fatalf("failed to create golden dir: %v", err)
+			return res
+		}
+
+		if err := os.WriteFile(file, actual, 0644); err != nil {
+			res.t.Fatalf("failed to write golden file: %v", err)
+		}
+		return res
+	}
+
+	expected, err := os.ReadFile(file)
+	if err != nil {
+		res.t.Fatalf("failed to read golden file %s, run the test with UPDATE_GOLDEN=1 to create it: %v", file, err)
+		return res
+	}
+
+	assert.Equal(res.t, string(expected), string(actual), "Expect response to match golden file %s", file)
+	return res
+}
+
+func maskPath(v interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return maskedValue
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if path[0] == "*" || path[0] == key {
+				value[key] = maskPath(child, path[1:])
+			}
+		}
+	case []interface{}:
+		for i, child := range value {
+			if path[0] == "*" || path[0] == strconv.Itoa(i) {
+				value[i] = maskPath(child, path[1:])
+			}
+		}
+	}
+
+	return v
+}
+
+func maskKeys(v interface{}, keys map[string]bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if keys[key] {
+				value[key] = maskedValue
+			} else {
+				value[key] = maskKeys(child, keys)
+			}
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = maskKeys(child, keys)
+		}
+	}
+
+	return v
+}
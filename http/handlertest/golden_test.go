@@ -0,0 +1,89 @@
+package handlertest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	phttp "github.com/agung-project/golib/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT record failure instead of failing the test
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.failed = true
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.failed = true
+}
+
+type event struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newEventHandler(name string) http.Handler {
+	return phttp.NewHttpHandlerV2(phttp.NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) phttp.HttpHandleResultV2 {
+		return phttp.HttpHandleResultV2{
+			Data: []event{{ID: time.Now().Format(time.RFC3339Nano), Name: name, CreatedAt: time.Now()}},
+		}
+	})
+}
+
+func TestGoldenEnvelopeV2(t *testing.T) {
+	Get(t, "/events").Do(newEventHandler("deploy")).
+		AssertGolden("events_v2", MaskFields("data.*.id"), MaskKeys("created_at"))
+}
+
+func TestGoldenErrorEnvelopeV2(t *testing.T) {
+	handler := phttp.NewHttpHandlerV2(phttp.NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) phttp.HttpHandleResultV2 {
+		return phttp.HttpHandleResultV2{Error: phttp.ErrUnauthorized}
+	})
+
+	Get(t, "/events").Do(handler).AssertGolden("unauthorized_v2")
+}
+
+func TestGoldenUpdateAndCompare(t *testing.T) {
+	dir := t.TempDir()
+	opts := []GoldenOption{GoldenDir(dir), MaskFields("data.*.id", "data.*.created_at")}
+
+	t.Setenv(updateGoldenEnv, "")
+
+	ft := &fakeT{TB: t}
+	Get(ft, "/events").Do(newEventHandler("deploy")).AssertGolden("events", opts...)
+	assert.True(t, ft.failed, "Expect failure when golden file does not exist")
+
+	t.Setenv(updateGoldenEnv, "1")
+	ft = &fakeT{TB: t}
+	Get(ft, "/events").Do(newEventHandler("deploy")).AssertGolden("events", opts...)
+	t.Setenv(updateGoldenEnv, "")
+	assert.False(t, ft.failed, "Expect golden file is written")
+
+	content, err := os.ReadFile(filepath.Join(dir, "events.golden.json"))
+	assert.Nil(t, err, "Expect golden file exist")
+	assert.Contains(t, string(content), `"created_at": "<masked>"`, "Expect volatile field is masked")
+	assert.Contains(t, string(content), `"http_status": 200`, "Expect http status is recorded")
+
+	ft = &fakeT{TB: t}
+	Get(ft, "/events").Do(newEventHandler("deploy")).AssertGolden("events", opts...)
+	assert.False(t, ft.failed, "Expect same envelope to match")
+
+	ft = &fakeT{TB: t}
+	Get(ft, "/events").Do(newEventHandler("release")).AssertGolden("events", opts...)
+	assert.True(t, ft.failed, "Expect changed envelope to fail")
+
+	ft = &fakeT{TB: t}
+	Get(ft, "/events").Do(newEventHandler("deploy")).AssertGolden("events", GoldenDir(dir))
+	assert.True(t, ft.failed, "Expect unmasked volatile field to fail")
+}
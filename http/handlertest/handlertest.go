@@ -0,0 +1,322 @@
+package handlertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	phttp "github.com/agung-project/golib/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type formFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// Request build http request fluently for handler test
+type Request struct {
+	t       testing.TB
+	method  string
+	target  string
+	header  http.Header
+	query   url.Values
+	body    []byte
+	form    url.Values
+	files   []formFile
+	ctx     context.Context
+	jsonErr error
+}
+
+func NewRequest(t testing.TB, method string, target string) *Request {
+	return &Request{
+		t:      t,
+		method: method,
+		target: target,
+		header: http.Header{},
+		query:  url.Values{},
+		form:   url.Values{},
+	}
+}
+
+func Get(t testing.TB, target string) *Request {
+	return NewRequest(t, http.MethodGet, target)
+}
+
+func Post(t testing.TB, target string) *Request {
+	return NewRequest(t, http.MethodPost, target)
+}
+
+func (r *Request) Header(key string, value string) *Request {
+	r.header.Add(key, value)
+	return r
+}
+
+func (r *Request) Query(key string, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *Request) BearerToken(token string) *Request {
+	r.header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func (r *Request) BasicAuth(username string, password string) *Request {
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+	r.header.Set("Authorization", req.Header.Get("Authorization"))
+	return r
+}
+
+// JSON set the body as JSON encoded value
+func (r *Request) JSON(v interface{}) *Request {
+	r.body, r.jsonErr = json.Marshal(v)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Body set the raw body
+func (r *Request) Body(body []byte, contentType string) *Request {
+	r.body = body
+	r.header.Set("Content-Type", contentType)
+	return r
+}
+
+// FormField add multipart form field
+func (r *Request) FormField(key string, value string) *Request {
+	r.form.Add(key, value)
+	return r
+}
+
+// File add multipart file, receive it using phttp.ReceiveFileToBytes(field, r)
+func (r *Request) File(field string, filename string, content []byte) *Request {
+	r.files = append(r.files, formFile{field: field, filename: filename, content: content})
+	return r
+}
+
+func (r *Request) Context(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Build return the http request
+func (r *Request) Build() *http.Request {
+	r.t.Helper()
+
+	if r.jsonErr != nil {
+		r.t.Fatalf("failed to encode JSON body: %v", r.jsonErr)
+	}
+
+	body := r.body
+	header := r.header.Clone()
+	if len(r.files) > 0 || len(r.form) > 0 {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		for key, values := range r.form {
+			for _, value := range values {
+				mw.WriteField(key, value)
+			}
+		}
+		for _, file := range r.files {
+			fw, err := mw.CreateFormFile(file.field, file.filename)
+			if err != nil {
+				r.t.Fatalf("failed to create form file: %v", err)
+			}
+			fw.Write(file.content)
+		}
+		mw.Close()
+
+		body = buf.Bytes()
+		header.Set("Content-Type", mw.FormDataContentType())
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req := httptest.NewRequest(r.method, r.target, reader)
+	req.Header = header
+
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for key, values := range r.query {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+
+	return req
+}
+
+// Do run the request through the handler (HttpHandler, HttpHandlerV2, Router or any http.Handler)
+func (r *Request) Do(h http.Handler) *Response {
+	r.t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r.Build())
+
+	return &Response{t: r.t, Recorder: w, Body: w.Body.Bytes()}
+}
+
+// Envelope is the decoded V1 (SuccessResponse/ErrorResponse) or V2 (ResponseV2) envelope
+type Envelope struct {
+	V2 bool
+	// StatusCode is the body status for V2 and http status for V1
+	StatusCode int
+	Success    bool
+	Message    []string
+	Data       json.RawMessage
+	Pagination *phttp.Pagination
+}
+
+// Response is the recorded response with assertion helpers
+type Response struct {
+	t        testing.TB
+	Recorder *httptest.ResponseRecorder
+	Body     []byte
+}
+
+func (res *Response) StatusCode() int {
+	return res.Recorder.Code
+}
+
+func (res *Response) Header() http.Header {
+	return res.Recorder.Header()
+}
+
+// Envelope decode the response body as V1 or V2 envelope
+func (res *Response) Envelope() Envelope {
+	res.t.Helper()
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(res.Body, &raw); err != nil {
+		res.t.Fatalf("failed to decode envelope: %v, body: %s", err, res.Body)
+	}
+
+	env := Envelope{Data: raw["data"]}
+	if _, ok := raw["success"]; ok {
+		env.V2 = true
+		json.Unmarshal(raw["status"], &env.StatusCode)
+		json.Unmarshal(raw["success"], &env.Success)
+		json.Unmarshal(raw["message"], &env.Message)
+
+		// paginated V2 data is SuccessResponseV2
+		data := map[string]json.RawMessage{}
+		if json.Unmarshal(env.Data, &data) == nil && isPagination(data) {
+			env.Pagination = &phttp.Pagination{}
+			json.Unmarshal(env.Data, env.Pagination)
+			env.Data = data["data"]
+		}
+
+		return env
+	}
+
+	env.StatusCode = res.Recorder.Code
+	env.Success = res.Recorder.Code < http.StatusBadRequest
+
+	var message string
+	json.Unmarshal(raw["message"], &message)
+	if message != "" {
+		env.Message = []string{message}
+	}
+
+	if isPagination(raw) {
+		env.Pagination = &phttp.Pagination{}
+		json.Unmarshal(res.Body, env.Pagination)
+	}
+
+	return env
+}
+
+func isPagination(raw map[string]json.RawMessage) bool {
+	_, hasPageSize := raw["page_size"]
+	_, hasTotalPage := raw["total_page"]
+	return hasPageSize && hasTotalPage
+}
+
+// Decode decode the envelope data into T
+func Decode[T any](res *Response) T {
+	res.t.Helper()
+
+	var data T
+	if err := json.Unmarshal(res.Envelope().Data, &data); err != nil {
+		res.t.Fatalf("failed to decode data: %v, body: %s", err, res.Body)
+	}
+
+	return data
+}
+
+// AssertStatus assert the http status code
+func (res *Response) AssertStatus(statusCode int) *Response {
+	res.t.Helper()
+	assert.Equal(res.t, statusCode, res.Recorder.Code, "Expect %d http status code", statusCode)
+	return res
+}
+
+// AssertEnvelopeStatus assert the status in the body for V2, or http status for V1
+func (res *Response) AssertEnvelopeStatus(statusCode int) *Response {
+	res.t.Helper()
+	assert.Equal(res.t, statusCode, res.Envelope().StatusCode, "Expect %d status code in body", statusCode)
+	return res
+}
+
+func (res *Response) AssertSuccess(success bool) *Response {
+	res.t.Helper()
+	assert.Equal(res.t, success, res.Envelope().Success, "Expect success %v", success)
+	return res
+}
+
+func (res *Response) AssertMessage(message ...string) *Response {
+	res.t.Helper()
+	assert.Equal(res.t, message, res.Envelope().Message, "Expect correct message")
+	return res
+}
+
+// AssertError assert the response is the error envelope of errorResponse
+func (res *Response) AssertError(errorResponse *phttp.ErrorResponse) *Response {
+	res.t.Helper()
+
+	env := res.Envelope()
+	assert.Equal(res.t, false, env.Success, "Expect success false")
+	assert.Equal(res.t, errorResponse.HttpStatus, env.StatusCode, "Expect %d error status code", errorResponse.HttpStatus)
+	assert.Contains(res.t, env.Message, errorResponse.ResponseDesc, "Expect error message")
+	return res
+}
+
+// AssertData assert the envelope data is equal to expected when both encoded as JSON
+func (res *Response) AssertData(expected interface{}) *Response {
+	res.t.Helper()
+
+	expectedJson, err := json.Marshal(expected)
+	if err != nil {
+		res.t.Fatalf("failed to encode expected data: %v", err)
+	}
+
+	assert.JSONEq(res.t, string(expectedJson), string(res.Envelope().Data), "Expect correct data")
+	return res
+}
+
+func (res *Response) AssertPagination(expected phttp.Pagination) *Response {
+	res.t.Helper()
+
+	env := res.Envelope()
+	if assert.NotNil(res.t, env.Pagination, "Expect pagination") {
+		assert.Equal(res.t, expected, *env.Pagination, "Expect correct pagination")
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+package handlertest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	phttp "github.com/agung-project/golib/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var errUserNotFound = errors.New("user not found")
+
+var errUserNotFoundResponse = &phttp.ErrorResponse{
+	Response:   phttp.Response{ResponseDesc: "User not found"},
+	HttpStatus: http.StatusNotFound,
+}
+
+func TestRequestBuilder(t *testing.T) {
+	req := Post(t, "/users?source=test").
+		JSON(user{ID: 1, Name: "agung"}).
+		Header("X-Request-Id", "abc").
+		Query("dry_run", "true").
+		BearerToken("token").
+		Build()
+
+	assert.Equal(t, http.MethodPost, req.Method, "Expect POST method")
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"), "Expect JSON content type")
+	assert.Equal(t, "abc", req.Header.Get("X-Request-Id"), "Expect request id header")
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"), "Expect bearer token")
+	assert.Equal(t, "test", req.URL.Query().Get("source"), "Expect original query is kept")
+	assert.Equal(t, "true", req.URL.Query().Get("dry_run"), "Expect added query")
+
+	var body user
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body), "Expect JSON body")
+	assert.Equal(t, user{ID: 1, Name: "agung"}, body, "Expect correct body")
+
+	req = Get(t, "/").BasicAuth("admin", "secret").Build()
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok, "Expect basic auth")
+	assert.Equal(t, "admin", username, "Expect username")
+	assert.Equal(t, "secret", password, "Expect password")
+}
+
+func TestMultipartRequest(t *testing.T) {
+	var received []byte
+	var description string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = phttp.ReceiveFileToBytes("file", r)
+		description = r.FormValue("description")
+	})
+
+	Post(t, "/upload").
+		File("file", "hello.txt", []byte("hello")).
+		FormField("description", "greeting").
+		Do(handler).
+		AssertStatus(http.StatusOK)
+
+	assert.Equal(t, "hello", string(received), "Expect uploaded file content")
+	assert.Equal(t, "greeting", description, "Expect form field")
+}
+
+func TestHandlerV2(t *testing.T) {
+	c := phttp.NewContextHandlerV2(false)
+	c.AddError(errUserNotFound, errUserNotFoundResponse)
+	newHandler := phttp.NewHttpHandlerV2(c)
+
+	handler := newHandler(func(w http.ResponseWriter, r *http.Request) phttp.HttpHandleResultV2 {
+		if r.URL.Query().Get("id") != "1" {
+			return phttp.HttpHandleResultV2{Error: errUserNotFound}
+		}
+
+		return phttp.HttpHandleResultV2{
+			Data:       []user{{ID: 1, Name: "agung"}},
+			Pagination: &phttp.Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 1, TotalData: 1},
+			Message:    []string{"found"},
+		}
+	})
+
+	res := Get(t, "/users").Query("id", "1").Do(handler).
+		AssertStatus(http.StatusOK).
+		AssertEnvelopeStatus(http.StatusOK).
+		AssertSuccess(true).
+		AssertMessage("found").
+		AssertData([]user{{ID: 1, Name: "agung"}}).
+		AssertPagination(phttp.Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 1, TotalData: 1})
+
+	users := Decode[[]user](res)
+	assert.Equal(t, []user{{ID: 1, Name: "agung"}}, users, "Expect typed data")
+
+	Get(t, "/users").Query("id", "2").Do(handler).
+		AssertStatus(http.StatusBadRequest).
+		AssertError(errUserNotFoundResponse)
+}
+
+func TestHandlerV1(t *testing.T) {
+	c := phttp.NewContextHandler(false)
+	c.AddError(errUserNotFound, errUserNotFoundResponse)
+	newHandler := phttp.NewHttpHandler(c)
+
+	handler := newHandler(func(w http.ResponseWriter, r *http.Request) phttp.HttpHandleResult {
+		if r.URL.Query().Get("id") != "1" {
+			return phttp.HttpHandleResult{Error: errUserNotFound}
+		}
+
+		return phttp.HttpHandleResult{Data: user{ID: 1, Name: "agung"}}
+	})
+
+	res := Get(t, "/users").Query("id", "1").Do(handler).
+		AssertStatus(http.StatusOK).
+		AssertSuccess(true).
+		AssertData([]user{{ID: 1, Name: "agung"}})
+
+	env := res.Envelope()
+	assert.False(t, env.V2, "Expect V1 envelope")
+
+	Get(t, "/users").Query("id", "2").Do(handler).
+		AssertStatus(http.StatusNotFound).
+		AssertError(errUserNotFoundResponse).
+		AssertMessage("User not found")
+}
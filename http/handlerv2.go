@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/agung-project/golib/trace"
 	"github.com/rs/zerolog/log"
 )
 
@@ -15,6 +17,15 @@ type HttpHandlerV2 struct {
 	H func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2
 	CustomWriterV2
 	IsDebug bool
+	// Timeout is deadline for H, zero means no timeout
+	Timeout time.Duration
+}
+
+// WithTimeout set deadline context for H, ErrGatewayTimeout will be returned when it elapses
+func WithTimeout(timeout time.Duration) HandlerV2Option {
+	return func(h *HttpHandlerV2) {
+		h.Timeout = timeout
+	}
 }
 
 func NewHttpHandlerV2(c HandlerContextV2, opts ...HandlerV2Option) func(handler func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2) HttpHandlerV2 {
@@ -30,29 +41,70 @@ func NewHttpHandlerV2(c HandlerContextV2, opts ...HandlerV2Option) func(handler
 }
 
 func (h HttpHandlerV2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r, span := startServerSpan(r)
+	defer span.End()
+
+	if h.Timeout > 0 {
+		h.serveWithTimeout(w, r)
+		return
+	}
+
+	h.serve(w, r)
+}
+
+func (h HttpHandlerV2) serve(w http.ResponseWriter, r *http.Request) {
 	result := h.H(w, r)
 
 	if h.IsDebug {
-		// Read the content
-		var bodyBytes []byte
-		bodyBytes, _ = ioutil.ReadAll(r.Body)
-		r.Body.Close() //  must close
-		// Restore the io.ReadCloser to its original state
-		r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-		// Use the content
-		bodyString := string(bodyBytes)
-		log.Logger.Info().Msgf("[DEBUG] Request: %v", bodyString)
+		logDebugRequest(r)
 	}
 
+	span := trace.SpanFromContext(r.Context())
+
+	writeResultHeaders(w, result.Headers, result.Cookies, result.InsecureCookies)
+
 	if result.Error != nil {
 		log.Logger.Error().Err(result.Error).Msgf("Response: %+v", result.Data)
+		errorResponse, statusCode := h.errorResponse(result.Error)
+		setRequestCode(r, errorResponse.HttpStatus)
+		span.SetError(result.Error)
+		span.SetAttribute("http.status_code", statusCode)
 		h.WriteError(w, result.Error, result.Message)
 		return
 	}
 
+	if result.RedirectURL != "" {
+		code := redirectStatus(result.StatusCode)
+		setRequestCode(r, code)
+		span.SetAttribute("http.status_code", code)
+		http.Redirect(w, r, result.RedirectURL, code)
+		return
+	}
+
+	code := result.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	setRequestCode(r, code)
+
 	if result.IsPlainResponse {
+		span.SetAttribute("http.status_code", code)
 		h.WritePlain(w, result.Data, result.StatusCode)
 	} else {
-		h.Write(w, result.Data, result.StatusCode, result.Pagination, result.Message)
+		span.SetAttribute("http.status_code", http.StatusOK)
+		h.Write(w, result.Data, result.StatusCode, writePaginationLinks(w, r, result.Pagination, h.C.PaginationLinks), result.Message)
 	}
 }
+
+// logDebugRequest log the request body and restore it, so it can be read again
+func logDebugRequest(r *http.Request) {
+	// Read the content
+	var bodyBytes []byte
+	bodyBytes, _ = ioutil.ReadAll(r.Body)
+	r.Body.Close() //  must close
+	// Restore the io.ReadCloser to its original state
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
+	// Use the content
+	bodyString := string(bodyBytes)
+	log.Logger.Info().Msgf("[DEBUG] Request: %v", bodyString)
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusDegraded = "degraded"
+)
+
+var errHealthCheckTimeout = errors.New("check timeout")
+
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Timeout default is 5 seconds
+	Timeout time.Duration
+	// Critical check failure make the service down, non critical failure only make it degraded
+	Critical bool
+	// Liveness include the check in /healthz, by default check is only included in /readyz
+	Liveness bool
+}
+
+type HealthCheckResult struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type HealthReport struct {
+	Status string              `json:"status"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// Health run the registered checks and expose them as /healthz and /readyz handlers,
+// check result is cached for CacheTTL to avoid hammering the dependencies
+type Health struct {
+	CustomWriterV2
+	CacheTTL time.Duration
+
+	mu      sync.Mutex
+	checks  []HealthCheck
+	results map[string]HealthCheckResult
+}
+
+func NewHealth(c HandlerContextV2, cacheTTL time.Duration) *Health {
+	return &Health{
+		CustomWriterV2: CustomWriterV2{C: c},
+		CacheTTL:       cacheTTL,
+		results:        map[string]HealthCheckResult{},
+	}
+}
+
+func (h *Health) Register(check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if check.Timeout <= 0 {
+		check.Timeout = 5 * time.Second
+	}
+	h.checks = append(h.checks, check)
+}
+
+// LivenessHandler run checks with Liveness flag, use it for /healthz
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Run(r.Context(), true))
+	})
+}
+
+// ReadinessHandler run all checks, use it for /readyz
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Run(r.Context(), false))
+	})
+}
+
+// Run execute the checks concurrently (or use cached result) and return the report
+func (h *Health) Run(ctx context.Context, livenessOnly bool) HealthReport {
+	h.mu.Lock()
+	checks := []HealthCheck{}
+	for _, check := range h.checks {
+		if !livenessOnly || check.Liveness {
+			checks = append(checks, check)
+		}
+	}
+	h.mu.Unlock()
+
+	report := HealthReport{Status: HealthStatusUp, Checks: make([]HealthCheckResult, len(checks))}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			report.Checks[i] = h.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == HealthStatusUp {
+			continue
+		}
+
+		if result.Critical {
+			report.Status = HealthStatusDown
+		} else if report.Status == HealthStatusUp {
+			report.Status = HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (h *Health) runCheck(ctx context.Context, check HealthCheck) HealthCheckResult {
+	h.mu.Lock()
+	cached, ok := h.results[check.Name]
+	h.mu.Unlock()
+
+	if ok && time.Since(cached.CheckedAt) < h.CacheTTL {
+		return cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	errChan := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errChan <- fmt.Errorf("check panic: %v", p)
+			}
+		}()
+		errChan <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errChan:
+	case <-ctx.Done():
+		err = errHealthCheckTimeout
+	}
+
+	result := HealthCheckResult{
+		Name:      check.Name,
+		Status:    HealthStatusUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	h.results[check.Name] = result
+	h.mu.Unlock()
+
+	return result
+}
+
+func (h *Health) writeReport(w http.ResponseWriter, report HealthReport) {
+	if report.Status != HealthStatusDown {
+		h.Write(w, report, http.StatusOK, nil, []string{report.Status})
+		return
+	}
+
+	h.writeErrorWithStatus(w, ErrServiceUnhealthy, report, http.StatusServiceUnavailable)
+}
+
+// PingCheck create check from dependency that has Ping method (e.g. oss.Pinger)
+func PingCheck(p interface {
+	Ping() error
+}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return p.Ping()
+	}
+}
+
+// PingContextCheck create check from dependency that has PingContext method (e.g. *sql.DB)
+func PingContextCheck(p interface {
+	PingContext(ctx context.Context) error
+}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return p.PingContext(ctx)
+	}
+}
+
+// RestClientCheck create check that call GET path of downstream service and expect 2xx response
+func RestClientCheck(c *RestClient, path string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		res, err := c.HttpClient.R().SetContext(ctx).Get(path)
+		if err != nil {
+			return err
+		}
+
+		if !res.IsSuccess() {
+			return fmt.Errorf("unexpected status %d", res.StatusCode())
+		}
+
+		return nil
+	}
+}
+
+// ServerReadyCheck create check that fail when the server is not ready (e.g. during shutdown)
+func ServerReadyCheck(s *Server) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if !s.IsReady() {
+			return ErrServerNotReady
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type healthResponse struct {
+	StatusCode int          `json:"status"`
+	Message    []string     `json:"message"`
+	Success    bool         `json:"success"`
+	Data       HealthReport `json:"data"`
+}
+
+func TestHealthReadiness(t *testing.T) {
+	health := NewHealth(NewContextHandlerV2(false), time.Minute)
+
+	dbCalls := 0
+	health.Register(HealthCheck{
+		Name:     "db",
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			dbCalls++
+			return nil
+		},
+	})
+	health.Register(HealthCheck{
+		Name: "cache",
+		Check: func(ctx context.Context) error {
+			return errors.New("connection refused")
+		},
+	})
+
+	request := func(handler http.Handler) (*httptest.ResponseRecorder, healthResponse) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		resp := healthResponse{}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	w, resp := request(health.ReadinessHandler())
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, true, resp.Success, "Expect Success True")
+	assert.Equal(t, HealthStatusDegraded, resp.Data.Status, "Expect degraded status")
+	assert.Equal(t, 2, len(resp.Data.Checks), "Expect 2 checks")
+	assert.Equal(t, HealthStatusUp, resp.Data.Checks[0].Status, "Expect db up")
+	assert.Equal(t, HealthStatusDown, resp.Data.Checks[1].Status, "Expect cache down")
+	assert.Equal(t, "connection refused", resp.Data.Checks[1].Error, "Expect cache error")
+
+	request(health.ReadinessHandler())
+	assert.Equal(t, 1, dbCalls, "Expect cached result used")
+
+	w, resp = request(health.LivenessHandler())
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, 0, len(resp.Data.Checks), "Expect no liveness check")
+}
+
+func TestHealthCriticalTimeout(t *testing.T) {
+	health := NewHealth(NewContextHandlerV2(false), 0)
+	health.Register(HealthCheck{
+		Name:     "oss",
+		Critical: true,
+		Liveness: true,
+		Timeout:  10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	health.LivenessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	resp := healthResponse{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Expect 503 status code")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "Expect 503 status code in body")
+	assert.Equal(t, false, resp.Success, "Expect Success False")
+	assert.Equal(t, HealthStatusDown, resp.Data.Status, "Expect down status")
+	assert.Equal(t, "check timeout", resp.Data.Checks[0].Error, "Expect timeout error")
+}
+
+func TestRestClientCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ping" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewRestClient(server.URL)
+	assert.NoError(t, RestClientCheck(client, "/ping")(context.Background()))
+	assert.EqualError(t, RestClientCheck(client, "/other")(context.Background()), "unexpected status 404")
+}
+
+type testPinger struct {
+	err error
+}
+
+func (p testPinger) Ping() error {
+	return p.err
+}
+
+func TestPingCheck(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, PingCheck(testPinger{})(ctx), "Expect ping success")
+	assert.EqualError(t, PingCheck(testPinger{err: errors.New("unreachable")})(ctx), "unreachable", "Expect ping error")
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IdempotencyRecord is the stored response, Completed is false while the first request is in flight
+type IdempotencyRecord struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Completed  bool
+}
+
+// IdempotencyStore keep the response per key, implement this for distributed store (e.g. redis)
+type IdempotencyStore interface {
+	// Start atomically reserve the key with in-flight record, started is false when the key already exist
+	// and record will contain the existing record
+	Start(ctx context.Context, key string, ttl time.Duration) (record *IdempotencyRecord, started bool, err error)
+	// Save store the completed response
+	Save(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Delete remove the key, so the request can be retried
+	Delete(ctx context.Context, key string) error
+}
+
+type IdempotencyConfig struct {
+	// Header default is Idempotency-Key
+	Header string
+	// Methods default is POST
+	Methods []string
+	// Required reject request without the header using ErrIdempotencyKeyRequired
+	Required bool
+	// Identity return the caller identity, default is the Authorization header
+	Identity func(r *http.Request) string
+	// TTL is how long the response is kept, default is 24 hours
+	TTL time.Duration
+	// LockTimeout is how long in-flight request hold the key, default is 1 minute
+	LockTimeout time.Duration
+	// Store default is in-memory store
+	Store IdempotencyStore
+}
+
+// NewIdempotencyMiddleware create middleware that replay the first response for duplicate request with same
+// Idempotency-Key and caller identity, duplicate of in-flight request will be rejected with ErrIdempotencyKeyInFlight
+func NewIdempotencyMiddleware(c HandlerContextV2, conf IdempotencyConfig) func(http.Handler) http.Handler {
+	writer := CustomWriterV2{C: c}
+
+	if conf.Header == "" {
+		conf.Header = "Idempotency-Key"
+	}
+
+	if len(conf.Methods) == 0 {
+		conf.Methods = []string{http.MethodPost}
+	}
+
+	if conf.Identity == nil {
+		conf.Identity = func(r *http.Request) string {
+			return r.Header.Get("Authorization")
+		}
+	}
+
+	if conf.TTL <= 0 {
+		conf.TTL = 24 * time.Hour
+	}
+
+	if conf.LockTimeout <= 0 {
+		conf.LockTimeout = time.Minute
+	}
+
+	if conf.Store == nil {
+		conf.Store = NewMemoryIdempotencyStore()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !containsMethod(conf.Methods, r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			idempotencyKey := r.Header.Get(conf.Header)
+			if idempotencyKey == "" {
+				if conf.Required {
+					writer.WriteError(w, ErrIdempotencyKeyRequired, nil)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			hash := sha256.Sum256([]byte(conf.Identity(r)))
+			key := r.Method + ":" + r.URL.Path + ":" + hex.EncodeToString(hash[:]) + ":" + idempotencyKey
+
+			record, started, err := conf.Store.Start(r.Context(), key, conf.LockTimeout)
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("error on start idempotency key")
+				writer.WriteError(w, err, nil)
+				return
+			}
+
+			if !started {
+				if record == nil || !record.Completed {
+					writer.WriteError(w, ErrIdempotencyKeyInFlight, nil)
+					return
+				}
+
+				replayIdempotencyRecord(w, record)
+				return
+			}
+
+			rec := &recordWriter{ResponseWriter: w}
+			completed := false
+			defer func() {
+				// handler panic or failed response, release the key so the request can be retried
+				if !completed {
+					if err := conf.Store.Delete(context.Background(), key); err != nil {
+						log.Logger.Error().Err(err).Msg("error on delete idempotency key")
+					}
+				}
+			}()
+
+			r, info := withRequestInfo(r)
+			next.ServeHTTP(rec, r)
+
+			// V2 writer send error as 400, so the envelope status recorded by the handler is checked too
+			if _, code := info.get(); !completedStatus(rec.StatusCode()) || (code != 0 && !completedStatus(code)) {
+				return
+			}
+
+			err = conf.Store.Save(context.Background(), key, IdempotencyRecord{
+				StatusCode: rec.StatusCode(),
+				Header:     w.Header().Clone(),
+				Body:       rec.body.Bytes(),
+				Completed:  true,
+			}, conf.TTL)
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("error on save idempotency key")
+				return
+			}
+
+			completed = true
+		})
+	}
+}
+
+// completedStatus return true for 2xx and 3xx status, error response is not stored so the request can be retried
+func completedStatus(code int) bool {
+	return code >= http.StatusOK && code < http.StatusBadRequest
+}
+
+func replayIdempotencyRecord(w http.ResponseWriter, record *IdempotencyRecord) {
+	dst := w.Header()
+	for k, v := range record.Header {
+		dst[k] = v
+	}
+	dst.Set("Idempotent-Replayed", "true")
+
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// recordWriter pass the response through while keeping a copy of status and body
+type recordWriter struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (rw *recordWriter) WriteHeader(code int) {
+	if rw.code == 0 {
+		rw.code = code
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordWriter) Write(p []byte) (int, error) {
+	if rw.code == 0 {
+		rw.code = http.StatusOK
+	}
+	rw.body.Write(p)
+	return rw.ResponseWriter.Write(p)
+}
+
+func (rw *recordWriter) StatusCode() int {
+	if rw.code == 0 {
+		return http.StatusOK
+	}
+	return rw.code
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiredAt time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryIdempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryIdempotencyStore create in-memory store, expired key will be removed on next access
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{
+		entries: map[string]*memoryIdempotencyEntry{},
+		now:     time.Now,
+	}
+}
+
+func (s *memoryIdempotencyStore) Start(ctx context.Context, key string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if now.After(entry.expiredAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if entry, ok := s.entries[key]; ok && !now.After(entry.expiredAt) {
+		record := entry.record
+		return &record, false, nil
+	}
+
+	s.entries[key] = &memoryIdempotencyEntry{expiredAt: now.Add(ttl)}
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryIdempotencyEntry{record: record, expiredAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	idempotency := NewIdempotencyMiddleware(handlerCtx, IdempotencyConfig{})
+
+	calls := 0
+	handler := idempotency(newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		calls++
+		w.Header().Set("X-Order-Id", "order-1")
+		response.Data = calls
+		response.StatusCode = http.StatusCreated
+		return
+	}))
+
+	request := func(key string, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("Idempotency-Key", key)
+		req.Header.Set("Authorization", auth)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request("key-1", "user-1")
+	second := request("key-1", "user-1")
+
+	assert.Equal(t, 1, calls, "Expect handler called once")
+	assert.Equal(t, first.Body.String(), second.Body.String(), "Expect same body replayed")
+	assert.Equal(t, first.Code, second.Code, "Expect same status replayed")
+	assert.Equal(t, "order-1", second.Header().Get("X-Order-Id"), "Expect header replayed")
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"), "Expect replayed header")
+
+	request("key-1", "user-2")
+	assert.Equal(t, 2, calls, "Expect different caller not replayed")
+}
+
+func TestIdempotencyMiddlewareInFlight(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	idempotency := NewIdempotencyMiddleware(handlerCtx, IdempotencyConfig{})
+
+	var inner *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = idempotency(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// duplicate request arrive while the first one is still processed
+		inner = httptest.NewRecorder()
+		handler.ServeHTTP(inner, r)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("Idempotency-Key", "key-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	body, _ := ioutil.ReadAll(inner.Result().Body)
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(body, respJson)
+
+	assert.Equal(t, http.StatusConflict, respJson.StatusCode, "Expect 409 status code in body")
+	assert.Equal(t, []string{ErrIdempotencyKeyInFlight.ResponseDesc}, respJson.Message, "Expect in flight message")
+}
+
+func TestIdempotencyMiddlewareServerError(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	idempotency := NewIdempotencyMiddleware(handlerCtx, IdempotencyConfig{Required: true})
+
+	calls := 0
+	handler := idempotency(newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		calls++
+		response.Error = ErrUnknown
+		return
+	}))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("Idempotency-Key", "key-1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Equal(t, 2, calls, "Expect server error not stored")
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(w.Body.Bytes(), respJson)
+	assert.Equal(t, []string{ErrIdempotencyKeyRequired.ResponseDesc}, respJson.Message, "Expect key required message")
+}
+
+func TestIdempotencyMiddlewareEnvelopeError(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	idempotency := NewIdempotencyMiddleware(handlerCtx, IdempotencyConfig{})
+
+	for _, errorResponse := range []*ErrorResponse{ErrTooManyRequests, ErrServiceUnhealthy, ErrUnauthorized} {
+		calls := 0
+		handler := idempotency(newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+			calls++
+			response.Error = errorResponse
+			return
+		}))
+
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set("Idempotency-Key", "key-"+errorResponse.ResponseDesc)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, "Expect V2 error http status")
+		}
+		assert.Equal(t, 2, calls, "Expect %s error sent as 400 not stored", errorResponse.ResponseDesc)
+	}
+}
+
+type failingIdempotencyStore struct {
+	IdempotencyStore
+	deleted []string
+}
+
+func (s *failingIdempotencyStore) Save(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func (s *failingIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return s.IdempotencyStore.Delete(ctx, key)
+}
+
+func TestIdempotencyMiddlewareSaveError(t *testing.T) {
+	store := &failingIdempotencyStore{IdempotencyStore: NewMemoryIdempotencyStore()}
+	idempotency := NewIdempotencyMiddleware(NewContextHandlerV2(false), IdempotencyConfig{Store: store})
+
+	calls := 0
+	handler := idempotency(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("Idempotency-Key", "key-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code, "Expect request processed, not blocked as in flight")
+	}
+
+	assert.Equal(t, 2, calls, "Expect key released after save error")
+	assert.Len(t, store.deleted, 2, "Expect key deleted after save error")
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type PaginationLinksConfig struct {
+	// PageField is the page query field used in page pagination links
+	PageField string
+	// AfterField and BeforeField are the cursor query fields used in cursor pagination links
+	AfterField  string
+	BeforeField string
+	// Header write RFC 8288 Link header
+	Header bool
+	// Body write links object in pagination
+	Body bool
+}
+
+type PaginationLinks struct {
+	First string `json:"first,omitempty" mapstructure:"first,omitempty"`
+	Prev  string `json:"prev,omitempty" mapstructure:"prev,omitempty"`
+	Next  string `json:"next,omitempty" mapstructure:"next,omitempty"`
+	Last  string `json:"last,omitempty" mapstructure:"last,omitempty"`
+}
+
+// NewPaginationLinks build links from the request url preserving the other query params,
+// cursor pagination has no last link. No link is built when the config has no field for the pagination mode
+func NewPaginationLinks(r *http.Request, pagination Pagination, conf PaginationLinksConfig) PaginationLinks {
+	links := PaginationLinks{}
+
+	if isCursorPagination(pagination) {
+		if conf.AfterField == "" || conf.BeforeField == "" {
+			return links
+		}
+
+		links.First = linkURL(r, map[string]string{conf.AfterField: "", conf.BeforeField: ""})
+		if pagination.NextCursor != "" {
+			links.Next = linkURL(r, map[string]string{conf.AfterField: pagination.NextCursor, conf.BeforeField: ""})
+		}
+		if pagination.PrevCursor != "" {
+			links.Prev = linkURL(r, map[string]string{conf.BeforeField: pagination.PrevCursor, conf.AfterField: ""})
+		}
+
+		return links
+	}
+
+	if conf.PageField == "" {
+		return links
+	}
+
+	page := func(n int) string {
+		return linkURL(r, map[string]string{conf.PageField: strconv.Itoa(n)})
+	}
+
+	links.First = page(1)
+	if pagination.CurrentPage > 1 {
+		links.Prev = page(pagination.CurrentPage - 1)
+	}
+	if pagination.CurrentPage < pagination.TotalPage {
+		links.Next = page(pagination.CurrentPage + 1)
+	}
+	if pagination.TotalPage > 0 {
+		links.Last = page(pagination.TotalPage)
+	}
+
+	return links
+}
+
+// LinkHeader format links as RFC 8288 Link header value
+func (l PaginationLinks) LinkHeader() string {
+	parts := []string{}
+	for _, link := range []struct{ rel, url string }{
+		{"first", l.First},
+		{"prev", l.Prev},
+		{"next", l.Next},
+		{"last", l.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, "<"+link.url+`>; rel="`+link.rel+`"`)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (c PaginationLinksConfig) enabled() bool {
+	return (c.Header || c.Body) && (c.PageField != "" || (c.AfterField != "" && c.BeforeField != ""))
+}
+
+// isCursorPagination return whether pagination is from GetCursorPagination, the last page of cursor pagination has
+// no cursor so it is told apart by the current page which is always set by GetNextPagination
+func isCursorPagination(pagination Pagination) bool {
+	return pagination.NextCursor != "" || pagination.PrevCursor != "" || pagination.CurrentPage == 0
+}
+
+// linkURL return the request path and query with the params replaced, empty value remove the param
+func linkURL(r *http.Request, params map[string]string) string {
+	query := r.URL.Query()
+	for key, value := range params {
+		if key == "" {
+			continue
+		}
+
+		if value == "" {
+			query.Del(key)
+		} else {
+			query.Set(key, value)
+		}
+	}
+
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// writePaginationLinks set the Link header and return copy of pagination with the links,
+// it must be called before the body is written
+func writePaginationLinks(w http.ResponseWriter, r *http.Request, pagination *Pagination, conf PaginationLinksConfig) *Pagination {
+	if pagination == nil || !conf.enabled() {
+		return pagination
+	}
+
+	links := NewPaginationLinks(r, *pagination, conf)
+	if conf.Header {
+		if header := links.LinkHeader(); header != "" {
+			w.Header().Set("Link", header)
+		}
+	}
+
+	if !conf.Body {
+		return pagination
+	}
+
+	withLinks := *pagination
+	withLinks.Links = &links
+
+	return &withLinks
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaginationLinks(t *testing.T) {
+	conf := PaginationLinksConfig{PageField: "page", AfterField: "after", BeforeField: "before"}
+
+	tests := []struct {
+		name       string
+		target     string
+		pagination Pagination
+		expected   PaginationLinks
+	}{
+		{
+			name:       "middle page",
+			target:     "/users?status=active&page=2&page_size=10",
+			pagination: Pagination{PageSize: 10, CurrentPage: 2, TotalPage: 3},
+			expected: PaginationLinks{
+				First: "/users?page=1&page_size=10&status=active",
+				Prev:  "/users?page=1&page_size=10&status=active",
+				Next:  "/users?page=3&page_size=10&status=active",
+				Last:  "/users?page=3&page_size=10&status=active",
+			},
+		},
+		{
+			name:       "first page",
+			target:     "/users",
+			pagination: Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 2},
+			expected:   PaginationLinks{First: "/users?page=1", Next: "/users?page=2", Last: "/users?page=2"},
+		},
+		{
+			name:       "empty result",
+			target:     "/users",
+			pagination: Pagination{PageSize: 10, CurrentPage: 1},
+			expected:   PaginationLinks{First: "/users?page=1"},
+		},
+		{
+			name:       "cursor",
+			target:     "/users?after=abc&limit=10",
+			pagination: Pagination{PageSize: 10, NextCursor: "next", PrevCursor: "prev", HasMore: true},
+			expected: PaginationLinks{
+				First: "/users?limit=10",
+				Prev:  "/users?before=prev&limit=10",
+				Next:  "/users?after=next&limit=10",
+			},
+		},
+		{
+			name:       "cursor last page",
+			target:     "/users?after=abc&limit=10",
+			pagination: Pagination{PageSize: 10},
+			expected:   PaginationLinks{First: "/users?limit=10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			assert.Equal(t, tt.expected, NewPaginationLinks(req, tt.pagination, conf), "Expect links")
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+	links := NewPaginationLinks(req, GetNextPagination(RequestPagination{Limit: 10, Offset: 10}, 25), PaginationLinksConfig{PageField: "page"})
+	assert.Equal(t, PaginationLinks{
+		First: "/users?page=1",
+		Prev:  "/users?page=1",
+		Next:  "/users?page=3",
+		Last:  "/users?page=3",
+	}, links, "Expect page links of GetNextPagination")
+
+	req = httptest.NewRequest(http.MethodGet, "/users?after=abc", nil)
+	links = NewPaginationLinks(req, Pagination{PageSize: 10}, PaginationLinksConfig{PageField: "page"})
+	assert.Equal(t, PaginationLinks{}, links, "Expect no cursor links without cursor fields")
+
+	assert.False(t, PaginationLinksConfig{AfterField: "after", Header: true}.enabled(), "Expect cursor links require before field")
+	assert.True(t, PaginationLinksConfig{AfterField: "after", BeforeField: "before", Header: true}.enabled(), "Expect cursor links enabled")
+}
+
+func TestPaginationLinksHandler(t *testing.T) {
+	c := NewContextHandlerV2(false)
+	c.PaginationLinks = PaginationLinksConfig{PageField: "page", AfterField: "after", BeforeField: "before", Header: true, Body: true}
+
+	nextPagination := GetNextPagination(RequestPagination{Limit: 10, Offset: 10}, 25)
+	pagination := &nextPagination
+	handler := NewHttpHandlerV2(c)(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		return HttpHandleResultV2{Data: []int{1}, Pagination: pagination}
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?page=2", nil))
+
+	assert.Equal(t, `</users?page=1>; rel="first", </users?page=1>; rel="prev", </users?page=3>; rel="next", </users?page=3>; rel="last"`, w.Header().Get("Link"), "Expect Link header")
+
+	resp := struct {
+		Data SuccessResponseV2 `json:"data"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.NotNil(t, resp.Data.Links, "Expect links in pagination") {
+		assert.Equal(t, "/users?page=3", resp.Data.Links.Next, "Expect next link")
+	}
+	assert.Nil(t, pagination.Links, "Expect handler pagination is not modified")
+
+	v1 := NewContextHandler(false)
+	v1.PaginationLinks = PaginationLinksConfig{PageField: "page", Header: true}
+	handlerV1 := NewHttpHandler(v1)(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		return HttpHandleResult{Data: []int{1}, Pagination: pagination}
+	})
+
+	w = httptest.NewRecorder()
+	handlerV1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?page=2", nil))
+	assert.Contains(t, w.Header().Get("Link"), `</users?page=3>; rel="next"`, "Expect V1 Link header")
+	assert.NotContains(t, w.Body.String(), `"links"`, "Expect no links in body")
+
+	w = httptest.NewRecorder()
+	NewHttpHandlerV2(NewContextHandlerV2(false))(handler.H).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.Empty(t, w.Header().Get("Link"), "Expect no Link header by default")
+
+	pageHandler := NewHttpHandlerV2(c)(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		return NewPage([]int{1}, RequestPagination{Limit: 10}, 25).ResultV2()
+	})
+
+	w = httptest.NewRecorder()
+	pageHandler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.Equal(t, `</users?page=1>; rel="first", </users?page=2>; rel="next", </users?page=3>; rel="last"`, w.Header().Get("Link"), "Expect Link header of Page")
+}
@@ -0,0 +1,342 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+// requestInfo is filled while the request is processed, so middleware outside the router
+// can know the matched route template and the envelope status code
+type requestInfo struct {
+	mu    sync.Mutex
+	route string
+	code  int
+}
+
+type requestInfoKey struct{}
+
+func withRequestInfo(r *http.Request) (*http.Request, *requestInfo) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		return r, info
+	}
+
+	info := &requestInfo{}
+	return r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info)), info
+}
+
+func setRequestRoute(r *http.Request, route string) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		info.mu.Lock()
+		info.route = route
+		info.mu.Unlock()
+	}
+}
+
+func setRequestCode(r *http.Request, code int) {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		info.mu.Lock()
+		info.code = code
+		info.mu.Unlock()
+	}
+}
+
+func (info *requestInfo) get() (route string, code int) {
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	return info.route, info.code
+}
+
+// Metrics collect request metrics of golib handlers and RestClient in Prometheus text format
+type Metrics struct {
+	requests       *metricVec
+	duration       *metricVec
+	inFlight       *metricVec
+	responseSize   *metricVec
+	clientRequests *metricVec
+	clientDuration *metricVec
+}
+
+// NewMetrics create metrics with namespace as metric name prefix (e.g. myservice_http_requests_total)
+func NewMetrics(namespace string) *Metrics {
+	prefix := ""
+	if namespace != "" {
+		prefix = namespace + "_"
+	}
+
+	serverLabels := []string{"route", "method", "status", "code"}
+	clientLabels := []string{"route", "method", "status"}
+
+	return &Metrics{
+		requests:       newMetricVec(prefix+"http_requests_total", "Total number of HTTP requests.", "counter", serverLabels, nil),
+		duration:       newMetricVec(prefix+"http_request_duration_seconds", "HTTP request latency in seconds.", "histogram", serverLabels, DefaultDurationBuckets),
+		inFlight:       newMetricVec(prefix+"http_requests_in_flight", "Number of HTTP requests being served.", "gauge", []string{"method"}, nil),
+		responseSize:   newMetricVec(prefix+"http_response_size_bytes", "HTTP response size in bytes.", "histogram", serverLabels, DefaultSizeBuckets),
+		clientRequests: newMetricVec(prefix+"http_client_requests_total", "Total number of outgoing HTTP requests.", "counter", clientLabels, nil),
+		clientDuration: newMetricVec(prefix+"http_client_request_duration_seconds", "Outgoing HTTP request latency in seconds.", "histogram", clientLabels, DefaultDurationBuckets),
+	}
+}
+
+// NewMetricsMiddleware create middleware that record request count, latency, in-flight and response size,
+// use it outside the Router so the route template is known
+func NewMetricsMiddleware(m *Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r, info := withRequestInfo(r)
+
+			// route is not matched yet, so in-flight gauge is labeled by method only
+			inFlightLabels := []string{r.Method}
+			m.inFlight.add(inFlightLabels, 1)
+			defer m.inFlight.add(inFlightLabels, -1)
+
+			sw := &sizeWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			route, code := info.get()
+			if route == "" {
+				route = RoutePattern(r)
+			}
+
+			codeLabel := ""
+			if code != 0 {
+				codeLabel = strconv.Itoa(code)
+			}
+
+			labels := []string{routeLabel(route), r.Method, strconv.Itoa(sw.StatusCode()), codeLabel}
+			m.requests.add(labels, 1)
+			m.duration.observe(labels, time.Since(start).Seconds())
+			m.responseSize.observe(labels, float64(sw.size))
+		})
+	}
+}
+
+// InstrumentRestClient record outgoing request count and latency, the route label is the request URL
+// before path params are applied, so use SetPathParam to keep the label cardinality low
+func (m *Metrics) InstrumentRestClient(c *RestClient) {
+	c.HttpClient.OnBeforeRequest(func(client *resty.Client, req *resty.Request) error {
+		// user defined hook run before resty apply the path params, keep the template for the label
+		if _, ok := req.Context().Value(clientRouteKey{}).(string); !ok {
+			req.SetContext(context.WithValue(req.Context(), clientRouteKey{}, req.URL))
+		}
+		return nil
+	})
+
+	c.HttpClient.OnAfterResponse(func(client *resty.Client, res *resty.Response) error {
+		labels := []string{clientRoute(res.Request), res.Request.Method, strconv.Itoa(res.StatusCode())}
+		m.clientRequests.add(labels, 1)
+		m.clientDuration.observe(labels, res.Time().Seconds())
+		return nil
+	})
+
+	c.HttpClient.OnError(func(req *resty.Request, err error) {
+		if _, ok := err.(*resty.ResponseError); ok {
+			return
+		}
+
+		labels := []string{clientRoute(req), req.Method, "error"}
+		m.clientRequests.add(labels, 1)
+		m.clientDuration.observe(labels, time.Since(req.Time).Seconds())
+	})
+}
+
+// Handler return handler that expose the metrics in Prometheus text format, register it as /metrics
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		m.WriteTo(&buf)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	})
+}
+
+// WriteTo write all metrics in Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, vec := range []*metricVec{m.requests, m.duration, m.inFlight, m.responseSize, m.clientRequests, m.clientDuration} {
+		n, err := vec.writeTo(w)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+type clientRouteKey struct{}
+
+func clientRoute(req *resty.Request) string {
+	if route, ok := req.Context().Value(clientRouteKey{}).(string); ok {
+		return route
+	}
+	return req.URL
+}
+
+func routeLabel(route string) string {
+	if route == "" {
+		return "unknown"
+	}
+	return route
+}
+
+// sizeWriter count the response size and keep the status code
+type sizeWriter struct {
+	http.ResponseWriter
+	code int
+	size int
+}
+
+func (sw *sizeWriter) WriteHeader(code int) {
+	if sw.code == 0 {
+		sw.code = code
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *sizeWriter) Write(p []byte) (int, error) {
+	if sw.code == 0 {
+		sw.code = http.StatusOK
+	}
+	n, err := sw.ResponseWriter.Write(p)
+	sw.size += n
+	return n, err
+}
+
+func (sw *sizeWriter) StatusCode() int {
+	if sw.code == 0 {
+		return http.StatusOK
+	}
+	return sw.code
+}
+
+type metricValue struct {
+	labels  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type metricVec struct {
+	mu         sync.Mutex
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	values     map[string]*metricValue
+}
+
+func newMetricVec(name string, help string, metricType string, labelNames []string, buckets []float64) *metricVec {
+	return &metricVec{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		values:     map[string]*metricValue{},
+	}
+}
+
+func (vec *metricVec) get(labels []string) *metricValue {
+	key := strings.Join(labels, "\xff")
+	v, ok := vec.values[key]
+	if !ok {
+		v = &metricValue{labels: append([]string{}, labels...), buckets: make([]uint64, len(vec.buckets))}
+		vec.values[key] = v
+	}
+
+	return v
+}
+
+func (vec *metricVec) add(labels []string, value float64) {
+	vec.mu.Lock()
+	defer vec.mu.Unlock()
+
+	vec.get(labels).value += value
+}
+
+func (vec *metricVec) observe(labels []string, value float64) {
+	vec.mu.Lock()
+	defer vec.mu.Unlock()
+
+	v := vec.get(labels)
+	for i, bound := range vec.buckets {
+		if value <= bound {
+			v.buckets[i]++
+		}
+	}
+	v.sum += value
+	v.count++
+}
+
+func (vec *metricVec) writeTo(w io.Writer) (int64, error) {
+	vec.mu.Lock()
+	defer vec.mu.Unlock()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# HELP %s %s\n", vec.name, vec.help)
+	fmt.Fprintf(&buf, "# TYPE %s %s\n", vec.name, vec.metricType)
+
+	keys := make([]string, 0, len(vec.values))
+	for key := range vec.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := vec.values[key]
+		labels := vec.formatLabels(v.labels)
+
+		if vec.metricType != "histogram" {
+			fmt.Fprintf(&buf, "%s{%s} %s\n", vec.name, labels, formatFloat(v.value))
+			continue
+		}
+
+		for i, bound := range vec.buckets {
+			fmt.Fprintf(&buf, "%s_bucket{%s,le=\"%s\"} %d\n", vec.name, labels, formatFloat(bound), v.buckets[i])
+		}
+		fmt.Fprintf(&buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", vec.name, labels, v.count)
+		fmt.Fprintf(&buf, "%s_sum{%s} %s\n", vec.name, labels, formatFloat(v.sum))
+		fmt.Fprintf(&buf, "%s_count{%s} %d\n", vec.name, labels, v.count)
+	}
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+func (vec *metricVec) formatLabels(values []string) string {
+	pairs := make([]string, len(vec.labelNames))
+	for i, name := range vec.labelNames {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", name, escapeLabelValue(values[i]))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
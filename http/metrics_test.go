@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	metrics := NewMetrics("test")
+
+	var customErr = errors.New("custom error")
+	handlerCtx.AddError(customErr, &ErrorResponse{
+		Response:   Response{ResponseDesc: "Custom Error"},
+		HttpStatus: http.StatusUnprocessableEntity,
+	})
+
+	router := NewRouter(handlerCtx)
+	router.Use(NewMetricsMiddleware(metrics))
+	inFlight := ""
+	router.Get("/users/{id}", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		if PathParam(r, "id") == "1" {
+			var buf bytes.Buffer
+			metrics.WriteTo(&buf)
+			inFlight = buf.String()
+		}
+
+		if PathParam(r, "id") == "0" {
+			response.Error = customErr
+			return
+		}
+		response.Data = "OK"
+		return
+	}))
+
+	for _, target := range []string{"/users/1", "/users/2", "/users/0", "/orders"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"), "Expect text exposition content type")
+	assert.Contains(t, body, "# TYPE test_http_requests_total counter\n")
+	assert.Contains(t, body, `test_http_requests_total{route="/users/{id}",method="GET",status="200",code="200"} 2`+"\n")
+	assert.Contains(t, body, `test_http_requests_total{route="/users/{id}",method="GET",status="400",code="422"} 1`+"\n")
+	assert.Contains(t, body, `test_http_requests_total{route="unknown",method="GET",status="400",code="404"} 1`+"\n")
+	assert.Contains(t, body, `test_http_request_duration_seconds_count{route="/users/{id}",method="GET",status="200",code="200"} 2`+"\n")
+	assert.Contains(t, body, `test_http_request_duration_seconds_bucket{route="/users/{id}",method="GET",status="200",code="200",le="+Inf"} 2`+"\n")
+	assert.Contains(t, inFlight, `test_http_requests_in_flight{method="GET"} 1`+"\n", "Expect request in flight")
+	assert.Contains(t, body, `test_http_requests_in_flight{method="GET"} 0`+"\n")
+	assert.Contains(t, body, `test_http_response_size_bytes_bucket{route="/users/{id}",method="GET",status="200",code="200",le="100"} 2`+"\n")
+}
+
+func TestMetricsRestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics("")
+	client := NewRestClient(server.URL)
+	metrics.InstrumentRestClient(client)
+
+	_, _ = client.HttpClient.R().SetPathParam("id", "1").Get("/users/{id}")
+
+	var sb strings.Builder
+	_, _ = metrics.WriteTo(&sb)
+	assert.Contains(t, sb.String(), `http_client_requests_total{route="/users/{id}",method="GET",status="404"} 1`+"\n")
+	assert.Contains(t, sb.String(), `http_client_request_duration_seconds_count{route="/users/{id}",method="GET",status="404"} 1`+"\n")
+}
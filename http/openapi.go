@@ -0,0 +1,438 @@
+package http
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIOperation describe a handler, Request, Query and Response are sample value of the binding structs (e.g. CreateUserRequest{})
+type OpenAPIOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	// Request is the JSON body
+	Request interface{}
+	// Query is struct with `query` tag for query parameters
+	Query interface{}
+	// Response is the data inside the envelope
+	Response interface{}
+	// Paginated wrap the response data with Pagination
+	Paginated bool
+	// V1 use HttpHandler envelope (SuccessResponse) instead of V2 (ResponseV2)
+	V1 bool
+	// Errors is the possible errors, looked up in the error registry. ErrUnknown is always documented
+	Errors []error
+}
+
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Example              interface{}               `json:"example,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema   *OpenAPISchema                    `json:"schema"`
+	Examples map[string]map[string]interface{} `json:"examples,omitempty"`
+}
+
+type openAPIBody struct {
+	Description string                      `json:"description,omitempty"`
+	Required    bool                        `json:"required,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIPathItem struct {
+	Summary     string                  `json:"summary,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Parameters  []openAPIParameter      `json:"parameters,omitempty"`
+	RequestBody *openAPIBody            `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIBody `json:"responses"`
+}
+
+type OpenAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       OpenAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]*openAPIPathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*OpenAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// OpenAPI generate OpenAPI 3 document from registered operations, errors are documented from the error registry (HandlerContext.E)
+type OpenAPI struct {
+	Info OpenAPIInfo
+	E    map[error]*ErrorResponse
+
+	mu         sync.Mutex
+	operations []OpenAPIOperation
+}
+
+func NewOpenAPI(info OpenAPIInfo, errMap map[error]*ErrorResponse) *OpenAPI {
+	return &OpenAPI{Info: info, E: errMap}
+}
+
+func (o *OpenAPI) Register(op OpenAPIOperation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op.Method = strings.ToUpper(op.Method)
+	o.operations = append(o.operations, op)
+}
+
+// Handle register the operation and the handler to the router
+func (o *OpenAPI) Handle(rt *Router, op OpenAPIOperation, handler http.Handler) {
+	o.Register(op)
+	rt.Handle(op.Method, op.Path, handler)
+}
+
+// Handler serve the JSON document, register it as /openapi.json
+func (o *OpenAPI) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, o.Document(), "application/json", http.StatusOK)
+	})
+}
+
+func (o *OpenAPI) Document() OpenAPIDocument {
+	o.mu.Lock()
+	operations := append([]OpenAPIOperation{}, o.operations...)
+	o.mu.Unlock()
+
+	g := &schemaGenerator{schemas: map[string]*OpenAPISchema{}, names: map[reflect.Type]string{}}
+	doc := OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    o.Info,
+		Paths:   map[string]map[string]*openAPIPathItem{},
+	}
+
+	for _, op := range operations {
+		path := openAPIPath(op.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openAPIPathItem{}
+		}
+		doc.Paths[path][strings.ToLower(op.Method)] = o.operation(g, op)
+	}
+	doc.Components.Schemas = g.schemas
+
+	return doc
+}
+
+func (o *OpenAPI) operation(g *schemaGenerator, op OpenAPIOperation) *openAPIPathItem {
+	item := &openAPIPathItem{
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]*openAPIBody{},
+	}
+
+	for _, segment := range splitPath(op.Path) {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			item.Parameters = append(item.Parameters, openAPIParameter{
+				Name:     strings.TrimSuffix(segment[1:len(segment)-1], "..."),
+				In:       "path",
+				Required: true,
+				Schema:   &OpenAPISchema{Type: "string"},
+			})
+		}
+	}
+
+	if op.Query != nil {
+		t := derefType(reflect.TypeOf(op.Query))
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := field.Tag.Get("query")
+			if name == "" || name == "-" || field.PkgPath != "" {
+				continue
+			}
+			item.Parameters = append(item.Parameters, openAPIParameter{
+				Name:   name,
+				In:     "query",
+				Schema: g.schema(field.Type),
+			})
+		}
+	}
+
+	if op.Request != nil {
+		item.RequestBody = &openAPIBody{
+			Required: true,
+			Content:  map[string]openAPIMediaType{"application/json": {Schema: g.schema(reflect.TypeOf(op.Request))}},
+		}
+	}
+
+	item.Responses[strconv.Itoa(http.StatusOK)] = &openAPIBody{
+		Description: "Success",
+		Content:     map[string]openAPIMediaType{"application/json": {Schema: o.successSchema(g, op)}},
+	}
+
+	o.errorResponses(g, op, item)
+
+	return item
+}
+
+func (o *OpenAPI) successSchema(g *schemaGenerator, op OpenAPIOperation) *OpenAPISchema {
+	var data *OpenAPISchema
+	if op.Response != nil {
+		data = g.schema(reflect.TypeOf(op.Response))
+	} else {
+		data = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{}}
+	}
+
+	if op.V1 {
+		// V1 writer always send data as array
+		if data.Type != "array" {
+			data = &OpenAPISchema{Type: "array", Items: data}
+		}
+
+		envelope := g.inline(reflect.TypeOf(SuccessResponse{}))
+		envelope.Properties["data"] = data
+		return envelope
+	}
+
+	if op.Paginated {
+		paginated := g.inline(reflect.TypeOf(SuccessResponseV2{}))
+		paginated.Properties["data"] = data
+		data = paginated
+	}
+
+	envelope := g.inline(reflect.TypeOf(ResponseV2{}))
+	envelope.Properties["data"] = data
+	return envelope
+}
+
+// errorResponses document the errors with the actual http status written by the writer,
+// V2 writer send 400 (500 for unknown error) with the error status in the body
+func (o *OpenAPI) errorResponses(g *schemaGenerator, op OpenAPIOperation, item *openAPIPathItem) {
+	errs := []*ErrorResponse{}
+	for _, err := range op.Errors {
+		errorResponse := LookupError(o.E, err)
+		if errorResponse == nil {
+			if e, ok := err.(*ErrorResponse); ok {
+				errorResponse = e
+			} else {
+				continue
+			}
+		}
+		errs = append(errs, errorResponse)
+	}
+	errs = append(errs, ErrUnknown)
+
+	for _, errorResponse := range errs {
+		statusCode := errorResponse.HttpStatus
+		if !op.V1 {
+			statusCode = http.StatusBadRequest
+			if errorResponse == ErrUnknown {
+				statusCode = http.StatusInternalServerError
+			}
+		}
+
+		key := strconv.Itoa(statusCode)
+		body, ok := item.Responses[key]
+		if !ok {
+			var schema *OpenAPISchema
+			if op.V1 {
+				schema = g.inline(reflect.TypeOf(ErrorResponse{}))
+			} else {
+				schema = g.inline(reflect.TypeOf(ResponseV2{}))
+				schema.Properties["data"] = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{}}
+			}
+
+			body = &openAPIBody{
+				Description: http.StatusText(statusCode),
+				Content: map[string]openAPIMediaType{"application/json": {
+					Schema:   schema,
+					Examples: map[string]map[string]interface{}{},
+				}},
+			}
+			item.Responses[key] = body
+		}
+
+		var example interface{} = errorResponse
+		if !op.V1 {
+			example = ResponseV2{
+				StatusCode: errorResponse.HttpStatus,
+				Message:    []string{errorResponse.ResponseDesc},
+				Data:       []interface{}{},
+			}
+		}
+		body.Content["application/json"].Examples[errorResponse.ResponseDesc] = map[string]interface{}{"value": example}
+	}
+}
+
+// openAPIPath convert router pattern to OpenAPI path, {path...} become {path}
+func openAPIPath(pattern string) string {
+	return strings.ReplaceAll(pattern, "...}", "}")
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type schemaGenerator struct {
+	schemas map[string]*OpenAPISchema
+	// names is the component name of each type, so types with the same name get different component
+	names map[reflect.Type]string
+}
+
+var (
+	// schemaTypeArgPath match package path of generic type argument, e.g. "github.com/org/" of "Page[github.com/org/models.User]"
+	schemaTypeArgPath = regexp.MustCompile(`[^\[\],*\s]+/`)
+	// schemaInvalidChar match character that is not allowed in component name
+	schemaInvalidChar = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// componentName return the component name of the type, qualified with the package name (e.g. models.User)
+// and sanitized for generic type (e.g. http.Page_models.User)
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	base := t.Name()
+	if t.PkgPath() != "" {
+		base = path.Base(t.PkgPath()) + "." + base
+	}
+	base = schemaTypeArgPath.ReplaceAllString(base, "")
+	base = strings.Trim(schemaInvalidChar.ReplaceAllString(base, "_"), "_")
+
+	name := base
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	g.names[t] = name
+
+	return name
+}
+
+// schema return schema of the type, named struct is added to components and referenced
+func (g *schemaGenerator) schema(t reflect.Type) *OpenAPISchema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var schema *OpenAPISchema
+	if t.Kind() == reflect.Struct && t != timeType && t.Name() != "" {
+		_, exist := g.names[t]
+		name := g.componentName(t)
+		if !exist {
+			// placeholder for recursive type
+			g.schemas[name] = &OpenAPISchema{}
+			*g.schemas[name] = *g.inline(t)
+		}
+		schema = &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	} else {
+		schema = g.inline(t)
+	}
+
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+
+	return schema
+}
+
+// inline return schema of the type without reference
+func (g *schemaGenerator) inline(t reflect.Type) *OpenAPISchema {
+	t = derefType(t)
+
+	switch {
+	case t == timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+		g.addFields(schema, t)
+		sort.Strings(schema.Required)
+		return schema
+	}
+
+	// interface{} can be anything
+	return &OpenAPISchema{}
+}
+
+func (g *schemaGenerator) addFields(schema *OpenAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && derefType(field.Type).Kind() == reflect.Struct {
+			// embedded struct fields are flattened like encoding/json
+			g.addFields(schema, derefType(field.Type))
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPITestUser struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Email     *string          `json:"email,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	Manager   *openAPITestUser `json:"manager,omitempty"`
+}
+
+type openAPITestCreateUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type openAPITestListUser struct {
+	Page   int    `query:"page"`
+	Search string `query:"search"`
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	var errUserExist = errors.New("user exist")
+	handlerCtx.AddError(errUserExist, &ErrorResponse{
+		Response:   Response{ResponseDesc: "User already exist"},
+		HttpStatus: http.StatusConflict,
+	})
+
+	api := NewOpenAPI(OpenAPIInfo{Title: "User API", Version: "1.0.0"}, handlerCtx.E)
+	router := NewRouter(handlerCtx)
+	api.Handle(router, OpenAPIOperation{
+		Method:   http.MethodPost,
+		Path:     "/users",
+		Request:  openAPITestCreateUser{},
+		Response: openAPITestUser{},
+		Errors:   []error{errUserExist, ErrUnauthorized},
+	}, http.NotFoundHandler())
+	api.Register(OpenAPIOperation{
+		Method:    http.MethodGet,
+		Path:      "/users",
+		Query:     openAPITestListUser{},
+		Response:  []openAPITestUser{},
+		Paginated: true,
+	})
+	api.Register(OpenAPIOperation{
+		Method:   http.MethodGet,
+		Path:     "/v1/users/{id}",
+		Response: openAPITestUser{},
+		V1:       true,
+		Errors:   []error{ErrUnauthorized},
+	})
+	router.Get("/openapi.json", api.Handler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+
+	doc := map[string]interface{}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &doc)
+	get := func(path ...interface{}) interface{} {
+		var value interface{} = doc
+		for _, p := range path {
+			switch key := p.(type) {
+			case string:
+				value = value.(map[string]interface{})[key]
+			case int:
+				value = value.([]interface{})[key]
+			}
+		}
+		return value
+	}
+
+	assert.Equal(t, "3.0.3", get("openapi"), "Expect openapi version")
+	assert.Equal(t, "User API", get("info", "title"), "Expect title")
+
+	// components
+	user := "#/components/schemas/http.openAPITestUser"
+	assert.Equal(t, "integer", get("components", "schemas", "http.openAPITestUser", "properties", "id", "type"), "Expect int64 id")
+	assert.Equal(t, "date-time", get("components", "schemas", "http.openAPITestUser", "properties", "created_at", "format"), "Expect time format")
+	assert.Equal(t, user, get("components", "schemas", "http.openAPITestUser", "properties", "manager", "$ref"), "Expect recursive reference")
+	assert.Equal(t, []interface{}{"created_at", "id", "name"}, get("components", "schemas", "http.openAPITestUser", "required"), "Expect required fields")
+
+	// V2 create user
+	post := []interface{}{"paths", "/users", "post"}
+	assert.Equal(t, "#/components/schemas/http.openAPITestCreateUser", get(append(post, "requestBody", "content", "application/json", "schema", "$ref")...), "Expect request body")
+	success := append(post, "responses", "200", "content", "application/json", "schema", "properties")
+	assert.Equal(t, "boolean", get(append(success, "success", "type")...), "Expect V2 envelope")
+	assert.Equal(t, user, get(append(success, "data", "$ref")...), "Expect data in envelope")
+	badRequest := append(post, "responses", "400", "content", "application/json", "examples")
+	assert.Equal(t, float64(http.StatusConflict), get(append(badRequest, "User already exist", "value", "status")...), "Expect registered error example")
+	assert.Equal(t, float64(http.StatusUnauthorized), get(append(badRequest, "You are not authorized", "value", "status")...), "Expect general error example")
+	assert.NotNil(t, get(append(post, "responses", "500")...), "Expect unknown error")
+
+	// V2 paginated list
+	list := []interface{}{"paths", "/users", "get"}
+	assert.Equal(t, "page", get(append(list, "parameters", 0, "name")...), "Expect query parameter")
+	paginated := append(list, "responses", "200", "content", "application/json", "schema", "properties", "data", "properties")
+	assert.Equal(t, "integer", get(append(paginated, "total_page", "type")...), "Expect pagination in data")
+	assert.Equal(t, user, get(append(paginated, "data", "items", "$ref")...), "Expect list in paginated data")
+
+	// V1
+	v1 := []interface{}{"paths", "/v1/users/{id}", "get"}
+	assert.Equal(t, "id", get(append(v1, "parameters", 0, "name")...), "Expect path parameter")
+	assert.Equal(t, "array", get(append(v1, "responses", "200", "content", "application/json", "schema", "properties", "data", "type")...), "Expect V1 data as array")
+	assert.Equal(t, "string", get(append(v1, "responses", "401", "content", "application/json", "schema", "properties", "message", "type")...), "Expect V1 error envelope")
+}
+
+type openAPITestPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestOpenAPISchemaName(t *testing.T) {
+	first := func() interface{} {
+		type item struct {
+			ID int `json:"id"`
+		}
+		return item{}
+	}()
+	second := func() interface{} {
+		type item struct {
+			Name string `json:"name"`
+		}
+		return item{}
+	}()
+
+	api := NewOpenAPI(OpenAPIInfo{Title: "Item API", Version: "1.0.0"}, nil)
+	api.Register(OpenAPIOperation{Method: http.MethodGet, Path: "/first", Response: first})
+	api.Register(OpenAPIOperation{Method: http.MethodGet, Path: "/second", Response: second})
+	api.Register(OpenAPIOperation{Method: http.MethodGet, Path: "/page", Response: openAPITestPage[openAPITestUser]{}})
+
+	w := httptest.NewRecorder()
+	api.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	doc := struct {
+		Components struct {
+			Schemas map[string]OpenAPISchema `json:"schemas"`
+		} `json:"components"`
+	}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &doc)
+
+	schemas := doc.Components.Schemas
+	assert.Contains(t, schemas, "http.item", "Expect name qualified with package")
+	assert.Contains(t, schemas, "http.item_2", "Expect type with the same name get different component")
+	assert.Contains(t, schemas["http.item"].Properties, "id", "Expect first type is not overwritten")
+	assert.Contains(t, schemas["http.item_2"].Properties, "name", "Expect second type is not overwritten")
+	assert.Contains(t, schemas, "http.openAPITestPage_http.openAPITestUser", "Expect sanitized generic name")
+	assert.Equal(t, "#/components/schemas/http.openAPITestUser", schemas["http.openAPITestPage_http.openAPITestUser"].Properties["items"].Items.Ref, "Expect type argument reference")
+}
@@ -0,0 +1,325 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Page is items of a page with its pagination
+type Page[T any] struct {
+	Items      []T
+	Pagination Pagination
+}
+
+// NewPage create page from the items of the request pagination and the total data
+func NewPage[T any](items []T, reqPage RequestPagination, total int64) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return Page[T]{Items: items, Pagination: GetNextPagination(reqPage, total)}
+}
+
+// Result return the page as handler result
+func (p Page[T]) Result() HttpHandleResult {
+	pagination := p.Pagination
+	return HttpHandleResult{Data: p.Items, Pagination: &pagination}
+}
+
+// ResultV2 return the page as handler V2 result
+func (p Page[T]) ResultV2() HttpHandleResultV2 {
+	pagination := p.Pagination
+	return HttpHandleResultV2{Data: p.Items, Pagination: &pagination}
+}
+
+// PaginateSlice filter, sort and slice items in memory using the parsed filters, sorts and request pagination,
+// Filter.Field and SortField.Name are resolved to struct field by json tag or field name before any item is read
+// so unknown field is an error even when items is empty. like filter is case sensitive contains, items is not modified
+func PaginateSlice[T any](items []T, reqPage RequestPagination, filters []Filter, sorts []SortField) (Page[T], error) {
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+
+	filterFields := make([]int, len(filters))
+	for i, filter := range filters {
+		index, err := fieldIndex(itemType, filter.Field)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		filterFields[i] = index
+	}
+
+	sortFields := make([]int, len(sorts))
+	for i, s := range sorts {
+		index, err := fieldIndex(itemType, s.Name)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		sortFields[i] = index
+	}
+
+	filtered := []T{}
+	for _, item := range items {
+		if matchFilters(reflect.ValueOf(item), filters, filterFields) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		for k, s := range sorts {
+			a := itemField(reflect.ValueOf(filtered[i]), sortFields[k])
+			b := itemField(reflect.ValueOf(filtered[j]), sortFields[k])
+
+			c, ok := compareFields(a, b)
+			if !ok || c == 0 {
+				continue
+			}
+
+			return (c < 0) != s.Desc
+		}
+
+		return false
+	})
+
+	total := int64(len(filtered))
+	if reqPage.Limit > 0 {
+		start := reqPage.Offset
+		if start < 0 {
+			start = 0
+		}
+		if start > len(filtered) {
+			start = len(filtered)
+		}
+
+		end := start + reqPage.Limit
+		if end > len(filtered) {
+			end = len(filtered)
+		}
+
+		filtered = filtered[start:end]
+	}
+
+	return NewPage(filtered, reqPage, total), nil
+}
+
+func matchFilters(item reflect.Value, filters []Filter, fields []int) bool {
+	for i, filter := range filters {
+		if !matchFilter(itemField(item, fields[i]), filter) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchFilter(field reflect.Value, filter Filter) bool {
+	if !field.IsValid() {
+		return false
+	}
+
+	compare := func(i int) (int, bool) {
+		if i >= len(filter.Values) {
+			return 0, false
+		}
+		return compareValues(field, reflect.ValueOf(filter.Values[i]))
+	}
+
+	switch filter.Operator {
+	case FilterLike:
+		return field.Kind() == reflect.String && len(filter.Values) == 1 &&
+			strings.Contains(field.String(), fmt.Sprint(filter.Values[0]))
+	case FilterIn, FilterNotIn:
+		found := false
+		for i := range filter.Values {
+			if c, ok := compare(i); ok && c == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (filter.Operator == FilterIn)
+	case FilterBetween:
+		low, okLow := compare(0)
+		high, okHigh := compare(1)
+		return okLow && okHigh && low >= 0 && high <= 0
+	}
+
+	c, ok := compare(0)
+	if !ok {
+		return false
+	}
+
+	switch filter.Operator {
+	case FilterEq:
+		return c == 0
+	case FilterNe:
+		return c != 0
+	case FilterGt:
+		return c > 0
+	case FilterGte:
+		return c >= 0
+	case FilterLt:
+		return c < 0
+	case FilterLte:
+		return c <= 0
+	}
+
+	return false
+}
+
+// fieldIndex return the index of the exported struct field with the json tag or field name
+func fieldIndex(t reflect.Type, name string) (int, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("paginate: item is not struct")
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == name || (tag == "" && strings.EqualFold(f.Name, name)) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("paginate: field %s not found", name)
+}
+
+// itemField return the struct field at index, nil item or nil pointer field return invalid value
+func itemField(item reflect.Value, index int) reflect.Value {
+	for item.Kind() == reflect.Ptr {
+		if item.IsNil() {
+			return reflect.Value{}
+		}
+		item = item.Elem()
+	}
+
+	value := item.Field(index)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// compareFields compare two item fields, nil field is before non nil field
+func compareFields(a reflect.Value, b reflect.Value) (int, bool) {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0, true
+	case !a.IsValid():
+		return -1, true
+	case !b.IsValid():
+		return 1, true
+	}
+
+	return compareValues(a, b)
+}
+
+// compareValues compare field value with filter value or other field value by kind so named type
+// (e.g. type Status string) is comparable with its underlying type, ok is false when they are not comparable
+func compareValues(field reflect.Value, value reflect.Value) (int, bool) {
+	if !field.IsValid() || !value.IsValid() {
+		return 0, false
+	}
+
+	if t, ok := field.Interface().(time.Time); ok {
+		other, ok := value.Interface().(time.Time)
+		if !ok {
+			return 0, false
+		}
+
+		switch {
+		case t.Before(other):
+			return -1, true
+		case t.After(other):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		if value.Kind() != reflect.String {
+			return 0, false
+		}
+		return strings.Compare(field.String(), value.String()), true
+	case reflect.Bool:
+		if value.Kind() != reflect.Bool {
+			return 0, false
+		}
+
+		a, b := 0, 0
+		if field.Bool() {
+			a = 1
+		}
+		if value.Bool() {
+			b = 1
+		}
+		return a - b, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isInt(field) && isInt(value) {
+			switch {
+			case field.Int() < value.Int():
+				return -1, true
+			case field.Int() > value.Int():
+				return 1, true
+			}
+			return 0, true
+		}
+
+		b, ok := toFloat(value)
+		if !ok {
+			return 0, false
+		}
+
+		a := numberValue(field)
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func isInt(v reflect.Value) bool {
+	return v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64
+}
+
+func numberValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return numberValue(v), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pageTestItem struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Price     float64   `json:"price"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	Note      *string   `json:"note"`
+}
+
+func pageTestItems() []pageTestItem {
+	note := "fragile"
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	return []pageTestItem{
+		{ID: 1, Name: "apple", Price: 1.5, Active: true, CreatedAt: day(3)},
+		{ID: 2, Name: "banana", Price: 0.5, Active: false, CreatedAt: day(1), Note: &note},
+		{ID: 3, Name: "cherry", Price: 5, Active: true, CreatedAt: day(2)},
+		{ID: 4, Name: "durian", Price: 12, Active: true, CreatedAt: day(5)},
+		{ID: 5, Name: "eggplant", Price: 1.5, Active: false, CreatedAt: day(4)},
+	}
+}
+
+func pageTestIDs(items []pageTestItem) []int {
+	ids := []int{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+func TestNewPage(t *testing.T) {
+	page := NewPage([]string(nil), RequestPagination{Limit: 10, Offset: 10}, 25)
+	assert.Equal(t, []string{}, page.Items, "Expect empty items instead of nil")
+	assert.Equal(t, 2, page.Pagination.CurrentPage, "Expect current page")
+	assert.Equal(t, 3, page.Pagination.TotalPage, "Expect total page")
+
+	result := page.ResultV2()
+	assert.Equal(t, page.Items, result.Data, "Expect items as data")
+	assert.Equal(t, page.Pagination, *result.Pagination, "Expect pagination")
+}
+
+func TestPaginateSlice(t *testing.T) {
+	schema := FilterSchema{
+		"name":       {Type: FilterString},
+		"price":      {Type: FilterFloat},
+		"active":     {Type: FilterBool},
+		"created_at": {Type: FilterTime},
+		"id":         {Type: FilterInt},
+	}
+	allowlist := SortAllowlist{"id": "id", "name": "name", "price": "price", "created_at": "created_at", "note": "note"}
+
+	tests := []struct {
+		name    string
+		query   string
+		reqPage RequestPagination
+		ids     []int
+		total   int
+	}{
+		{name: "all", query: "", reqPage: RequestPagination{}, ids: []int{1, 2, 3, 4, 5}, total: 5},
+		{name: "limit offset", query: "", reqPage: RequestPagination{Limit: 2, Offset: 2}, ids: []int{3, 4}, total: 5},
+		{name: "offset beyond total", query: "", reqPage: RequestPagination{Limit: 2, Offset: 10}, ids: []int{}, total: 5},
+		{name: "negative offset", query: "", reqPage: RequestPagination{Limit: 2, Offset: -3}, ids: []int{1, 2}, total: 5},
+		{name: "eq bool", query: "active=true", ids: []int{1, 3, 4}, total: 3},
+		{name: "gte float", query: "price=gte:1.5", ids: []int{1, 3, 4, 5}, total: 4},
+		{name: "in int", query: "id=in:2,4", ids: []int{2, 4}, total: 2},
+		{name: "nin int", query: "id=nin:2,4", ids: []int{1, 3, 5}, total: 3},
+		{name: "like", query: "name=like:an", ids: []int{2, 4, 5}, total: 3},
+		{name: "between time", query: "created_at=between:2024-01-02,2024-01-04", ids: []int{1, 3, 5}, total: 3},
+		{name: "sort desc", query: "sort=-price,id", ids: []int{4, 3, 1, 5, 2}, total: 5},
+		{name: "sort desc with nil field", query: "sort=-note,id", ids: []int{2, 1, 3, 4, 5}, total: 5},
+		{name: "filter sort and page", query: "active=true&sort=-created_at", reqPage: RequestPagination{Limit: 2}, ids: []int{4, 1}, total: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			filters, err := ParseFilters(query, schema)
+			assert.Nil(t, err, "Expect valid filters")
+
+			sorts, err := ParseSort(query.Get("sort"), allowlist)
+			assert.Nil(t, err, "Expect valid sort")
+
+			page, err := PaginateSlice(pageTestItems(), tt.reqPage, filters, sorts)
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, tt.ids, pageTestIDs(page.Items), "Expect items")
+			assert.Equal(t, tt.total, page.Pagination.TotalData, "Expect total data")
+		})
+	}
+}
+
+func TestPaginateSliceUnknownField(t *testing.T) {
+	_, err := PaginateSlice(pageTestItems(), RequestPagination{}, []Filter{{Field: "unknown", Operator: FilterEq, Values: []interface{}{"a"}}}, nil)
+	assert.NotNil(t, err, "Expect error for unknown filter field")
+
+	_, err = PaginateSlice(pageTestItems(), RequestPagination{}, nil, []SortField{{Name: "unknown"}})
+	assert.NotNil(t, err, "Expect error for unknown sort field")
+
+	_, err = PaginateSlice([]pageTestItem{}, RequestPagination{}, nil, []SortField{{Name: "unknown"}})
+	assert.NotNil(t, err, "Expect error for unknown sort field without items")
+
+	_, err = PaginateSlice(pageTestItems()[:1], RequestPagination{}, nil, []SortField{{Name: "unknown"}})
+	assert.NotNil(t, err, "Expect error for unknown sort field with 1 item")
+}
+
+type pageTestStatus string
+
+type pageTestNamedItem struct {
+	ID     int            `json:"id"`
+	Status pageTestStatus `json:"status"`
+}
+
+func TestPaginateSliceNamedType(t *testing.T) {
+	items := []*pageTestNamedItem{{ID: 1, Status: "paid"}, {ID: 2, Status: "new"}, {ID: 3, Status: "paid"}}
+	filters := []Filter{{Field: "status", Operator: FilterEq, Values: []interface{}{"paid"}}}
+
+	page, err := PaginateSlice(items, RequestPagination{}, filters, []SortField{{Name: "id", Desc: true}})
+	assert.Nil(t, err, "Expect no error")
+	if assert.Len(t, page.Items, 2, "Expect named string type is filtered") {
+		assert.Equal(t, 3, page.Items[0].ID, "Expect sorted desc")
+	}
+
+	page, err = PaginateSlice(items, RequestPagination{}, nil, []SortField{{Name: "status"}, {Name: "id"}})
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, 2, page.Items[0].ID, "Expect named string type is sorted")
+}
+
+func TestPaginateSliceHandler(t *testing.T) {
+	handler := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		reqPage := GetPagination(r, 2, PaginationFields{LimitField: "page_size", OffsetField: "page"})
+		page, err := PaginateSlice(pageTestItems(), reqPage, nil, nil)
+		if err != nil {
+			return HttpHandleResultV2{Error: err}
+		}
+
+		return page.ResultV2()
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?page=3", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Contains(t, w.Body.String(), `"current_page":3`, "Expect current page")
+	assert.Contains(t, w.Body.String(), `"name":"eggplant"`, "Expect last item")
+}
@@ -1,55 +1,162 @@
 package http
 
 import (
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 )
 
+type PaginationConfig struct {
+	Fields          PaginationFields
+	DefaultPageSize int
+	// MinPageSize and MaxPageSize bound the page size, zero means no bound
+	MinPageSize int
+	MaxPageSize int
+	// Strict return ErrInvalidPage or error that wrap ErrInvalidPageSize for invalid value sent by the client
+	// instead of correcting it
+	Strict bool
+}
+
 func GetPagination(r *http.Request, defaultPageSize int, fields PaginationFields) RequestPagination {
+	reqPage, _ := GetPaginationWithConfig(r, PaginationConfig{Fields: fields, DefaultPageSize: defaultPageSize})
+	return reqPage
+}
+
+// GetPaginationWithConfig parse the request pagination, invalid page is corrected to 1 and page size is corrected
+// to the default or clamped to the bound, unless Strict is set
+func GetPaginationWithConfig(r *http.Request, conf PaginationConfig) (RequestPagination, error) {
+	fields := conf.Fields
 	query := r.URL.Query().Get(fields.QueryField)
 	sort := r.URL.Query().Get(fields.SortField)
-	page, _ := strconv.Atoi(r.URL.Query().Get(fields.OffsetField))
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get(fields.LimitField))
 
-	sorts := []string{}
-	if sort != "" {
-		sorts = strings.Split(sort, ",")
+	page, err := parsePaginationInt(r.URL.Query().Get(fields.OffsetField), 1)
+	if err != nil || page < 1 {
+		if conf.Strict {
+			return RequestPagination{}, ErrInvalidPage
+		}
+		page = 1
 	}
 
-	if page == 0 {
-		page = 1
+	// page size that is not sent is corrected without error even in strict mode
+	pageSizeValue := r.URL.Query().Get(fields.LimitField)
+	strict := conf.Strict && pageSizeValue != ""
+
+	pageSize, err := parsePaginationInt(pageSizeValue, conf.DefaultPageSize)
+	if err != nil || (pageSize < 1 && pageSizeValue != "") {
+		if strict {
+			return RequestPagination{}, invalidPageSizeError(conf)
+		}
+		pageSize = conf.DefaultPageSize
 	}
 
-	if pageSize == 0 {
-		pageSize = defaultPageSize
+	if conf.MinPageSize > 0 && pageSize < conf.MinPageSize {
+		if strict {
+			return RequestPagination{}, invalidPageSizeError(conf)
+		}
+		pageSize = conf.MinPageSize
+	}
+
+	if conf.MaxPageSize > 0 && pageSize > conf.MaxPageSize {
+		if strict {
+			return RequestPagination{}, invalidPageSizeError(conf)
+		}
+		pageSize = conf.MaxPageSize
+	}
+
+	// offset of huge page overflow, so the page is limited to the last page with offset that fit in int
+	if pageSize > 0 && page-1 > math.MaxInt/pageSize {
+		if conf.Strict {
+			return RequestPagination{}, ErrInvalidPage
+		}
+		page = math.MaxInt/pageSize + 1
+	}
+
+	sorts := []string{}
+	if sort != "" {
+		sorts = strings.Split(sort, ",")
 	}
 
-	return RequestPagination{
+	reqPage := RequestPagination{
 		Query:  query,
 		Sort:   sorts,
 		Limit:  pageSize,
 		Offset: (page - 1) * pageSize,
 	}
+
+	if fields.AfterField != "" {
+		reqPage.After = r.URL.Query().Get(fields.AfterField)
+	}
+
+	if fields.BeforeField != "" {
+		reqPage.Before = r.URL.Query().Get(fields.BeforeField)
+	}
+
+	return reqPage, nil
+}
+
+// parsePaginationInt return defaultValue when value is empty
+func parsePaginationInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
 }
 
+// invalidPageSizeError wrap ErrInvalidPageSize with the bound, so errors.Is(err, ErrInvalidPageSize) is true
+func invalidPageSizeError(conf PaginationConfig) error {
+	switch {
+	case conf.MinPageSize > 0 && conf.MaxPageSize > 0:
+		return fmt.Errorf("%w, page size must be between %d and %d", ErrInvalidPageSize, conf.MinPageSize, conf.MaxPageSize)
+	case conf.MaxPageSize > 0:
+		return fmt.Errorf("%w, page size must be between 1 and %d", ErrInvalidPageSize, conf.MaxPageSize)
+	case conf.MinPageSize > 0:
+		return fmt.Errorf("%w, page size must be at least %d", ErrInvalidPageSize, conf.MinPageSize)
+	}
+
+	return ErrInvalidPageSize
+}
+
+// GetNextPagination create page pagination from the request pagination and the total data,
+// zero limit means all data is in one page. Current page is clamped to [1, TotalPage], so empty result has
+// CurrentPage 1 and TotalPage 0. NextPage and PrevPage are the current page when there is no next or previous page
 func GetNextPagination(reqPage RequestPagination, dataCount int64) Pagination {
 	count := int(dataCount)
-	currentPage := (reqPage.Offset / reqPage.Limit) + 1
 	pageSize := reqPage.Limit
 
-	totalPage := count / pageSize
-	if count%pageSize > 0 {
-		totalPage++
+	currentPage := 1
+	totalPage := 0
+	if pageSize > 0 {
+		currentPage = (reqPage.Offset / pageSize) + 1
+		totalPage = count / pageSize
+		if count%pageSize > 0 {
+			totalPage++
+		}
+	} else if count > 0 {
+		totalPage = 1
 	}
 
-	if currentPage >= totalPage {
+	if currentPage > totalPage {
 		currentPage = totalPage
 	}
 
-	nextPage := currentPage + 1
-	if nextPage >= totalPage {
-		nextPage = totalPage
+	if currentPage < 1 {
+		currentPage = 1
+	}
+
+	hasNext := currentPage < totalPage
+	hasPrev := currentPage > 1
+
+	nextPage := currentPage
+	if hasNext {
+		nextPage = currentPage + 1
+	}
+
+	prevPage := currentPage
+	if hasPrev {
+		prevPage = currentPage - 1
 	}
 
 	return Pagination{
@@ -58,5 +165,8 @@ func GetNextPagination(reqPage RequestPagination, dataCount int64) Pagination {
 		TotalPage:   totalPage,
 		NextPage:    nextPage,
 		TotalData:   count,
+		PrevPage:    prevPage,
+		HasNext:     hasNext,
+		HasPrev:     hasPrev,
 	}
 }
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -62,4 +64,120 @@ func TestGetNextPagination(t *testing.T) {
 	assert.Equal(t, 4, nextPage.NextPage, "NextPage should be 4")
 	assert.Equal(t, 3, nextPage.CurrentPage, "CurrentPage should be 3")
 	assert.Equal(t, 7, nextPage.TotalPage, "TotalPage should be 7")
-}
\ No newline at end of file
+}
+
+func TestGetPaginationWithConfig(t *testing.T) {
+	fields := PaginationFields{LimitField: "page_size", OffsetField: "page"}
+
+	tests := []struct {
+		name   string
+		query  string
+		conf   PaginationConfig
+		limit  int
+		offset int
+		err    string
+	}{
+		{name: "default", query: "", conf: PaginationConfig{DefaultPageSize: 10}, limit: 10, offset: 0},
+		{name: "page and size", query: "page=3&page_size=5", conf: PaginationConfig{DefaultPageSize: 10}, limit: 5, offset: 10},
+		{name: "negative page", query: "page=-2", conf: PaginationConfig{DefaultPageSize: 10}, limit: 10, offset: 0},
+		{name: "zero page", query: "page=0", conf: PaginationConfig{DefaultPageSize: 10}, limit: 10, offset: 0},
+		{name: "invalid page", query: "page=abc", conf: PaginationConfig{DefaultPageSize: 10}, limit: 10, offset: 0},
+		{name: "negative page size", query: "page_size=-5", conf: PaginationConfig{DefaultPageSize: 10}, limit: 10, offset: 0},
+		{name: "clamp max", query: "page=2&page_size=1000", conf: PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, limit: 100, offset: 100},
+		{name: "clamp min", query: "page_size=2", conf: PaginationConfig{DefaultPageSize: 10, MinPageSize: 5}, limit: 5, offset: 0},
+		{name: "strict valid", query: "page=2&page_size=20", conf: PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, Strict: true}, limit: 20, offset: 20},
+		{name: "strict default", query: "", conf: PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, Strict: true}, limit: 10, offset: 0},
+		{name: "strict negative page", query: "page=-1", conf: PaginationConfig{DefaultPageSize: 10, Strict: true}, err: "Invalid page"},
+		{name: "strict invalid page", query: "page=abc", conf: PaginationConfig{DefaultPageSize: 10, Strict: true}, err: "Invalid page"},
+		{name: "strict zero page size", query: "page_size=0", conf: PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, Strict: true}, err: "Invalid page size, page size must be between 1 and 100"},
+		{name: "strict max", query: "page_size=101", conf: PaginationConfig{DefaultPageSize: 10, MinPageSize: 5, MaxPageSize: 100, Strict: true}, err: "Invalid page size, page size must be between 5 and 100"},
+		{name: "strict min", query: "page_size=2", conf: PaginationConfig{DefaultPageSize: 10, MinPageSize: 5, Strict: true}, err: "Invalid page size, page size must be at least 5"},
+		{name: "strict no default", query: "page=2", conf: PaginationConfig{Strict: true}, limit: 0, offset: 0},
+		{name: "strict no default with min", query: "", conf: PaginationConfig{MinPageSize: 5, Strict: true}, limit: 5, offset: 0},
+		{name: "overflow page", query: "page=9223372036854775807&page_size=20", conf: PaginationConfig{DefaultPageSize: 10}, limit: 20, offset: math.MaxInt / 20 * 20},
+		{name: "strict overflow page", query: "page=9223372036854775807&page_size=20", conf: PaginationConfig{DefaultPageSize: 10, Strict: true}, err: "Invalid page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.conf.Fields = fields
+			req := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+			page, err := GetPaginationWithConfig(req, tt.conf)
+			if tt.err != "" {
+				target := ErrInvalidPageSize
+				if tt.err == ErrInvalidPage.ResponseDesc {
+					target = ErrInvalidPage
+				}
+
+				if assert.NotNil(t, err, "Expect error") {
+					assert.True(t, errors.Is(err, target), "Expect error to match %s", target)
+					assert.Equal(t, tt.err, err.Error(), "Expect error message")
+				}
+				return
+			}
+
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, tt.limit, page.Limit, "Expect limit")
+			assert.Equal(t, tt.offset, page.Offset, "Expect offset")
+		})
+	}
+}
+
+func TestGetNextPaginationEdgeCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqPage  RequestPagination
+		count    int64
+		expected Pagination
+	}{
+		{
+			name:     "empty result",
+			reqPage:  RequestPagination{Limit: 10},
+			count:    0,
+			expected: Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 0, NextPage: 1, PrevPage: 1},
+		},
+		{
+			name:     "zero limit",
+			reqPage:  RequestPagination{Limit: 0},
+			count:    25,
+			expected: Pagination{PageSize: 0, CurrentPage: 1, TotalPage: 1, NextPage: 1, PrevPage: 1, TotalData: 25},
+		},
+		{
+			name:     "first page",
+			reqPage:  RequestPagination{Limit: 10},
+			count:    25,
+			expected: Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 3, NextPage: 2, PrevPage: 1, TotalData: 25, HasNext: true},
+		},
+		{
+			name:     "middle page",
+			reqPage:  RequestPagination{Limit: 10, Offset: 10},
+			count:    25,
+			expected: Pagination{PageSize: 10, CurrentPage: 2, TotalPage: 3, NextPage: 3, PrevPage: 1, TotalData: 25, HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "last page",
+			reqPage:  RequestPagination{Limit: 10, Offset: 20},
+			count:    25,
+			expected: Pagination{PageSize: 10, CurrentPage: 3, TotalPage: 3, NextPage: 3, PrevPage: 2, TotalData: 25, HasPrev: true},
+		},
+		{
+			name:     "page beyond total",
+			reqPage:  RequestPagination{Limit: 10, Offset: 90},
+			count:    25,
+			expected: Pagination{PageSize: 10, CurrentPage: 3, TotalPage: 3, NextPage: 3, PrevPage: 2, TotalData: 25, HasPrev: true},
+		},
+		{
+			name:     "exact page",
+			reqPage:  RequestPagination{Limit: 5},
+			count:    5,
+			expected: Pagination{PageSize: 5, CurrentPage: 1, TotalPage: 1, NextPage: 1, PrevPage: 1, TotalData: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetNextPagination(tt.reqPage, tt.count), "Expect pagination")
+		})
+	}
+}
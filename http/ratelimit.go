@@ -0,0 +1,246 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit is token bucket limit, Limit is the refill rate in tokens per second and Burst is the bucket size
+type RateLimit struct {
+	Limit rate.Limit
+	Burst int
+}
+
+// NewRateLimit create limit that allow n requests per period, with n as the bucket size.
+// It panics when n or per is not positive
+func NewRateLimit(n int, per time.Duration) RateLimit {
+	if n <= 0 || per <= 0 {
+		panic(fmt.Sprintf("ratelimit: invalid limit %d per %s", n, per))
+	}
+
+	return RateLimit{
+		Limit: rate.Every(per / time.Duration(n)),
+		Burst: n,
+	}
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the duration until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the duration until next token is available, only set when not allowed
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keep the token buckets, implement this for distributed store (e.g. redis)
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// RateLimitKeyFunc return the client identity for the bucket, empty key will fallback to client IP
+type RateLimitKeyFunc func(r *http.Request) string
+
+type RateLimitConfig struct {
+	// Name is the bucket namespace, use different name for each route to have separate limit per route
+	Name  string
+	Limit RateLimit
+	// Key default is RateLimitByIP
+	Key RateLimitKeyFunc
+	// Store default is in-memory store
+	Store RateLimitStore
+}
+
+// NewRateLimitMiddleware create middleware that limit request using token bucket per key,
+// exceeded request will be rejected with ErrTooManyRequests in V2 envelope and 429 http status.
+// It panics when the limit or burst is not positive
+func NewRateLimitMiddleware(c HandlerContextV2, conf RateLimitConfig) func(http.Handler) http.Handler {
+	if conf.Limit.Limit <= 0 || conf.Limit.Burst <= 0 {
+		panic(fmt.Sprintf("ratelimit: invalid limit %v with burst %d", conf.Limit.Limit, conf.Limit.Burst))
+	}
+
+	writer := CustomWriterV2{C: c}
+
+	if conf.Key == nil {
+		conf.Key = RateLimitByIP
+	}
+
+	if conf.Store == nil {
+		conf.Store = NewMemoryRateLimitStore(0)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := conf.Key(r)
+			if key == "" {
+				key = RateLimitByIP(r)
+			}
+
+			result, err := conf.Store.Take(r.Context(), conf.Name+":"+key, conf.Limit)
+			if err != nil {
+				// fail open, rate limiter should not take the service down
+				log.Logger.Error().Err(err).Msg("error on take rate limit token")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				writer.writeErrorWithStatus(w, ErrTooManyRequests, nil, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitByIP use the remote address as the key
+func RateLimitByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// RateLimitByForwardedIP use X-Forwarded-For or X-Real-IP header as the key,
+// only use this when the service is behind trusted proxy
+func RateLimitByForwardedIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+
+	return RateLimitByIP(r)
+}
+
+// RateLimitByHeader use header value (e.g. API key) as the key
+func RateLimitByHeader(header string) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		if value := r.Header.Get(header); value != "" {
+			return header + ":" + value
+		}
+
+		return ""
+	}
+}
+
+// RateLimitByContext use request context value (e.g. authenticated user id set by auth middleware) as the key
+func RateLimitByContext(key interface{}) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		if value := r.Context().Value(key); value != nil {
+			return fmt.Sprintf("ctx:%v", value)
+		}
+
+		return ""
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	ttl       time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore create in-memory store, bucket not used longer than ttl will be removed (default 10 minutes)
+func NewMemoryRateLimitStore(ttl time.Duration) RateLimitStore {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+
+	return &memoryRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (res RateLimitResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		s.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(burst, bucket.tokens+elapsed*float64(limit.Limit))
+	}
+	bucket.last = now
+
+	res.Limit = limit.Burst
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = tokensDuration(limit.Limit, 1-bucket.tokens)
+	}
+
+	res.Remaining = int(bucket.tokens)
+	res.Reset = tokensDuration(limit.Limit, burst-bucket.tokens)
+
+	return
+}
+
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.last) > s.ttl {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+func tokensDuration(limit rate.Limit, tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	if limit <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(tokens / float64(limit) * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
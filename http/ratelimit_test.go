@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rateLimit := NewRateLimitMiddleware(NewContextHandlerV2(false), RateLimitConfig{
+		Name:  "test",
+		Limit: NewRateLimit(2, time.Minute),
+		Key:   RateLimitByHeader("X-Api-Key"),
+	})
+	handler := rateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("key-1")
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"), "Expect limit header")
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"), "Expect 1 remaining")
+
+	w = request("key-1")
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"), "Expect 0 remaining")
+
+	w = request("key-1")
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(body, respJson)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "Expect 429 http status")
+	assert.Equal(t, http.StatusTooManyRequests, respJson.StatusCode, "Expect 429 status code in body")
+	assert.Equal(t, false, respJson.Success, "Expect Success False")
+	assert.Equal(t, "30", w.Header().Get("Retry-After"), "Expect retry after 30 seconds")
+
+	w = request("key-2")
+	assert.Equal(t, http.StatusOK, w.Code, "Expect other key has its own bucket")
+}
+
+func TestMemoryRateLimitStoreRefill(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore(time.Minute).(*memoryRateLimitStore)
+	store.now = func() time.Time { return now }
+	limit := NewRateLimit(1, time.Second)
+
+	res, _ := store.Take(context.Background(), "ip", limit)
+	assert.Equal(t, true, res.Allowed, "Expect first request allowed")
+
+	res, _ = store.Take(context.Background(), "ip", limit)
+	assert.Equal(t, false, res.Allowed, "Expect second request rejected")
+	assert.Equal(t, time.Second, res.RetryAfter, "Expect retry after 1 second")
+
+	now = now.Add(time.Second)
+	res, _ = store.Take(context.Background(), "ip", limit)
+	assert.Equal(t, true, res.Allowed, "Expect request allowed after refill")
+
+	now = now.Add(2 * time.Minute)
+	_, _ = store.Take(context.Background(), "other", limit)
+	assert.Equal(t, 1, len(store.buckets), "Expect stale bucket removed")
+}
+
+func TestNewRateLimitInvalid(t *testing.T) {
+	assert.Panics(t, func() { NewRateLimit(0, time.Minute) }, "Expect zero limit is rejected")
+	assert.Panics(t, func() { NewRateLimit(-1, time.Minute) }, "Expect negative limit is rejected")
+	assert.Panics(t, func() { NewRateLimit(1, 0) }, "Expect zero period is rejected")
+
+	c := NewContextHandlerV2(false)
+	assert.Panics(t, func() { NewRateLimitMiddleware(c, RateLimitConfig{}) }, "Expect unset limit is rejected")
+	assert.Panics(t, func() {
+		NewRateLimitMiddleware(c, RateLimitConfig{Limit: RateLimit{Limit: 1}})
+	}, "Expect zero burst is rejected")
+}
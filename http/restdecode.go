@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// APIError is error response (non 2xx or success false) of golib service, use errors.Is to match it
+// with the same ErrorResponse variable used by the service, e.g. errors.Is(err, phttp.ErrUnauthorized)
+type APIError struct {
+	// HttpStatus is the http status of the response
+	HttpStatus int
+	// StatusCode is the status in V2 body, or the http status for V1
+	StatusCode int
+	Messages   []string
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, strings.Join(e.Messages, ", "))
+}
+
+// Is match ErrorResponse with the same status and message. The envelope has no error code so the match is by
+// the message text, error written with custom message (e.g. by ErrorResultV2) does not match its ErrorResponse, and
+// ErrorResponse with the same status and message can not be told apart, compare StatusCode and Messages for those
+func (e *APIError) Is(target error) bool {
+	errorResponse, ok := target.(*ErrorResponse)
+	if !ok || errorResponse.HttpStatus != e.StatusCode {
+		return false
+	}
+
+	for _, message := range e.Messages {
+		if message == errorResponse.ResponseDesc {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Call send request with JSON body (nil for no body) and decode the envelope data into T
+func Call[T any](ctx context.Context, c *RestClient, method string, path string, body interface{}) (T, error) {
+	req := c.HttpClient.R().SetContext(ctx)
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	return DecodeResponse[T](req.Execute(method, path))
+}
+
+// DecodeResponse decode V1 (SuccessResponse) or V2 (ResponseV2) envelope data into T, error response is returned as *APIError.
+// It accept the result of resty request directly, e.g. DecodeResponse[User](client.HttpClient.R().Get("/users/1"))
+func DecodeResponse[T any](res *resty.Response, err error) (T, error) {
+	var data T
+
+	raw, err := decodeEnvelope(res, err)
+	if err != nil {
+		return data, err
+	}
+
+	if _, ok := raw["success"]; ok {
+		// paginated V2 data contains the pagination and the items
+		var paginated map[string]json.RawMessage
+		if json.Unmarshal(raw["data"], &paginated) == nil && paginated["page_size"] != nil && paginated["total_page"] != nil {
+			return data, decodeEnvelopeData(paginated["data"], &data)
+		}
+	}
+
+	return data, decodeEnvelopeData(raw["data"], &data)
+}
+
+// DecodePageResponse decode V1 or V2 paginated envelope into Page, error response is returned as *APIError
+func DecodePageResponse[T any](res *resty.Response, err error) (Page[T], error) {
+	if _, err := decodeEnvelope(res, err); err != nil {
+		return Page[T]{}, err
+	}
+
+	return decodePage[T](res.Body())
+}
+
+// decodeEnvelope return the envelope fields, or *APIError when the response is error
+func decodeEnvelope(res *resty.Response, err error) (map[string]json.RawMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]json.RawMessage{}
+	jsonErr := json.Unmarshal(res.Body(), &raw)
+
+	if jsonErr == nil && !res.IsError() {
+		var success bool
+		if value, ok := raw["success"]; !ok || (json.Unmarshal(value, &success) == nil && success) {
+			return raw, nil
+		}
+	}
+
+	apiErr := &APIError{HttpStatus: res.StatusCode(), StatusCode: res.StatusCode(), Body: res.Body()}
+	if jsonErr != nil {
+		if !res.IsError() {
+			return nil, jsonErr
+		}
+
+		apiErr.Messages = []string{http.StatusText(res.StatusCode())}
+		return nil, apiErr
+	}
+
+	if _, ok := raw["success"]; ok {
+		json.Unmarshal(raw["status"], &apiErr.StatusCode)
+		json.Unmarshal(raw["message"], &apiErr.Messages)
+	} else {
+		var message string
+		json.Unmarshal(raw["message"], &message)
+		apiErr.Messages = []string{message}
+	}
+
+	return nil, apiErr
+}
+
+// decodeEnvelopeData decode data into v, V1 writer wrap non slice data in array so single element array is unwrapped
+func decodeEnvelopeData(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	err := json.Unmarshal(data, v)
+	if err == nil || !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return err
+	}
+
+	items := []json.RawMessage{}
+	if json.Unmarshal(data, &items) != nil || len(items) > 1 {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(items[0], v)
+}
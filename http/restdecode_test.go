@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type restDecodeUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newRestDecodeServer() *httptest.Server {
+	user := restDecodeUser{ID: 1, Name: "agung"}
+	fields := PaginationFields{LimitField: "page_size", OffsetField: "page"}
+
+	v1 := NewHttpHandler(NewContextHandler(false))
+	v2 := NewHttpHandlerV2(NewContextHandlerV2(false))
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/user", v1(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		return HttpHandleResult{Data: user}
+	}))
+	mux.Handle("/v1/unauthorized", v1(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		return HttpHandleResult{Error: ErrUnauthorized}
+	}))
+	mux.Handle("/v2/user", v2(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		return HttpHandleResultV2{Data: user}
+	}))
+	mux.Handle("/v2/users", v2(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		page, _ := PaginateSlice([]restDecodeUser{user, {ID: 2, Name: "budi"}}, GetPagination(r, 10, fields), nil, nil)
+		return page.ResultV2()
+	}))
+	mux.Handle("/v2/unauthorized", v2(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		return HttpHandleResultV2{Error: ErrUnauthorized}
+	}))
+	mux.HandleFunc("/gateway", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestCall(t *testing.T) {
+	server := newRestDecodeServer()
+	defer server.Close()
+	client := NewRestClient(server.URL)
+	ctx := context.Background()
+
+	for _, path := range []string{"/v1/user", "/v2/user"} {
+		user, err := Call[restDecodeUser](ctx, client, http.MethodGet, path, nil)
+		assert.Nil(t, err, path)
+		assert.Equal(t, restDecodeUser{ID: 1, Name: "agung"}, user, path)
+	}
+
+	users, err := Call[[]restDecodeUser](ctx, client, http.MethodGet, "/v2/users", nil)
+	assert.Nil(t, err, "Expect paginated data")
+	assert.Len(t, users, 2, "Expect paginated items")
+
+	page, err := DecodePageResponse[restDecodeUser](client.HttpClient.R().Get("/v2/users"))
+	assert.Nil(t, err, "Expect page")
+	assert.Equal(t, 2, page.Pagination.TotalData, "Expect pagination")
+}
+
+func TestCallError(t *testing.T) {
+	server := newRestDecodeServer()
+	defer server.Close()
+	client := NewRestClient(server.URL)
+	ctx := context.Background()
+
+	for _, path := range []string{"/v1/unauthorized", "/v2/unauthorized"} {
+		_, err := Call[restDecodeUser](ctx, client, http.MethodGet, path, nil)
+		assert.True(t, errors.Is(err, ErrUnauthorized), path)
+		assert.False(t, errors.Is(err, ErrInvalidHeader), path)
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr), path)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode, path)
+		assert.Equal(t, []string{ErrUnauthorized.ResponseDesc}, apiErr.Messages, path)
+	}
+
+	_, err := Call[restDecodeUser](ctx, client, http.MethodGet, "/gateway", nil)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr), "Expect error of non JSON response")
+	assert.Equal(t, http.StatusBadGateway, apiErr.HttpStatus, "Expect http status")
+	assert.Equal(t, []string{"Bad Gateway"}, apiErr.Messages, "Expect status text")
+
+	apiErr = &APIError{HttpStatus: http.StatusBadRequest, StatusCode: ErrInvalidSort.HttpStatus, Messages: []string{"invalid sort, allowed fields: name"}}
+	assert.False(t, errors.Is(apiErr, ErrInvalidSort), "Expect custom message does not match")
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// ErrMaxPagesExceeded is returned after MaxPages pages are fetched and the endpoint still has more pages
+var ErrMaxPagesExceeded = errors.New("max pages exceeded")
+
+// PageRequest is paginated GET request of V1 or V2 endpoint, the envelope version is detected from the response
+type PageRequest struct {
+	Path  string
+	Query url.Values
+	// PageField is the page query field, default is "page"
+	PageField string
+	// AfterField is the cursor query field used when the response has next_cursor, default is "after"
+	AfterField string
+	// Concurrency is how many pages are fetched concurrently after the first page, default is 1.
+	// Cursor pagination is always fetched sequentially
+	Concurrency int
+	// MaxPages default is 100
+	MaxPages int
+}
+
+// PageResult is fetched page or the error that stop the fetching
+type PageResult[T any] struct {
+	Page Page[T]
+	Err  error
+}
+
+// FetchAllPages fetch all pages and return all items, items fetched before the error are returned with the error
+func FetchAllPages[T any](ctx context.Context, c *RestClient, req PageRequest) ([]T, error) {
+	items := []T{}
+	for result := range StreamPages[T](ctx, c, req) {
+		if result.Err != nil {
+			return items, result.Err
+		}
+
+		items = append(items, result.Page.Items...)
+	}
+
+	return items, nil
+}
+
+// StreamPages fetch the pages in order to the channel, the channel is closed after the last page or the first error.
+// Caller must read the channel until it is closed or cancel ctx
+func StreamPages[T any](ctx context.Context, c *RestClient, req PageRequest) <-chan PageResult[T] {
+	if req.PageField == "" {
+		req.PageField = "page"
+	}
+
+	if req.AfterField == "" {
+		req.AfterField = "after"
+	}
+
+	if req.Concurrency <= 0 {
+		req.Concurrency = 1
+	}
+
+	if req.MaxPages <= 0 {
+		req.MaxPages = 100
+	}
+
+	out := make(chan PageResult[T])
+	go func() {
+		defer close(out)
+
+		send := func(result PageResult[T]) bool {
+			select {
+			case out <- result:
+				return result.Err == nil
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		first, err := fetchPage[T](ctx, c, req, nil)
+		if !send(PageResult[T]{Page: first, Err: err}) {
+			return
+		}
+
+		if first.Pagination.NextCursor != "" {
+			streamCursorPages(ctx, c, req, first, send)
+			return
+		}
+
+		streamNumberedPages(ctx, c, req, first, send)
+	}()
+
+	return out
+}
+
+func streamCursorPages[T any](ctx context.Context, c *RestClient, req PageRequest, page Page[T], send func(PageResult[T]) bool) {
+	for fetched := 1; page.Pagination.NextCursor != ""; fetched++ {
+		if fetched >= req.MaxPages {
+			send(PageResult[T]{Err: ErrMaxPagesExceeded})
+			return
+		}
+
+		var err error
+		page, err = fetchPage[T](ctx, c, req, map[string]string{req.AfterField: page.Pagination.NextCursor})
+		if !send(PageResult[T]{Page: page, Err: err}) {
+			return
+		}
+	}
+}
+
+func streamNumberedPages[T any](ctx context.Context, c *RestClient, req PageRequest, first Page[T], send func(PageResult[T]) bool) {
+	lastPage := first.Pagination.TotalPage
+	exceeded := lastPage > req.MaxPages
+	if exceeded {
+		lastPage = req.MaxPages
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// each page has its own channel, so pages fetched concurrently are still sent in order
+	results := make([]chan PageResult[T], 0)
+	for page := first.Pagination.CurrentPage + 1; page <= lastPage; page++ {
+		results = append(results, make(chan PageResult[T], 1))
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		sem := make(chan struct{}, req.Concurrency)
+		for i := range results {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				for ; i < len(results); i++ {
+					results[i] <- PageResult[T]{Err: ctx.Err()}
+				}
+				return
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				page := first.Pagination.CurrentPage + 1 + i
+				p, err := fetchPage[T](ctx, c, req, map[string]string{req.PageField: strconv.Itoa(page)})
+				results[i] <- PageResult[T]{Page: p, Err: err}
+			}(i)
+		}
+	}()
+
+	for _, result := range results {
+		if !send(<-result) {
+			return
+		}
+	}
+
+	if exceeded {
+		send(PageResult[T]{Err: ErrMaxPagesExceeded})
+	}
+}
+
+func fetchPage[T any](ctx context.Context, c *RestClient, req PageRequest, params map[string]string) (Page[T], error) {
+	query := url.Values{}
+	for key, values := range req.Query {
+		query[key] = append([]string{}, values...)
+	}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	return DecodePageResponse[T](c.HttpClient.R().SetContext(ctx).SetQueryParamsFromValues(query).Get(req.Path))
+}
+
+// decodePage decode V1 (SuccessResponse) or V2 (ResponseV2) paginated envelope
+func decodePage[T any](body []byte) (Page[T], error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return Page[T]{}, err
+	}
+
+	data := body
+	if _, ok := raw["success"]; ok {
+		data = raw["data"]
+
+		// V2 data without pagination is the items
+		items := []T{}
+		if json.Unmarshal(data, &items) == nil {
+			return Page[T]{Items: items}, nil
+		}
+	}
+
+	envelope := struct {
+		Pagination
+		Data []T `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Page[T]{}, err
+	}
+
+	if envelope.Data == nil {
+		envelope.Data = []T{}
+	}
+
+	return Page[T]{Items: envelope.Data, Pagination: envelope.Pagination}, nil
+}
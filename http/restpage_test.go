@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type restPageItem struct {
+	ID int `json:"id"`
+}
+
+func restPageItems(n int) []restPageItem {
+	items := make([]restPageItem, n)
+	for i := range items {
+		items[i] = restPageItem{ID: i + 1}
+	}
+
+	return items
+}
+
+func newRestPageServer(t *testing.T, total int, failPage int) (*httptest.Server, *int32) {
+	var calls int32
+	fields := PaginationFields{LimitField: "page_size", OffsetField: "page"}
+
+	v1 := NewHttpHandler(NewContextHandler(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		atomic.AddInt32(&calls, 1)
+		page, _ := PaginateSlice(restPageItems(total), GetPagination(r, 10, fields), nil, nil)
+		return page.Result()
+	})
+
+	v2 := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("page") == strconv.Itoa(failPage) {
+			return HttpHandleResultV2{Error: ErrUnauthorized}
+		}
+
+		page, _ := PaginateSlice(restPageItems(total), GetPagination(r, 10, fields), nil, nil)
+		return page.ResultV2()
+	})
+
+	cursor := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		atomic.AddInt32(&calls, 1)
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		reqPage := RequestPagination{Limit: 10, After: r.URL.Query().Get("after")}
+
+		rows := []restPageItem{}
+		for _, item := range restPageItems(total) {
+			if item.ID > after && len(rows) <= reqPage.Limit {
+				rows = append(rows, item)
+			}
+		}
+
+		rows, hasMore := TrimCursorRows(reqPage, rows)
+		pagination := GetCursorPagination(reqPage, hasMore, strconv.Itoa(rows[0].ID), strconv.Itoa(rows[len(rows)-1].ID))
+		return HttpHandleResultV2{Data: rows, Pagination: &pagination}
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/items", v1)
+	mux.Handle("/v2/items", v2)
+	mux.Handle("/cursor/items", cursor)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestFetchAllPages(t *testing.T) {
+	server, calls := newRestPageServer(t, 95, 0)
+	client := NewRestClient(server.URL)
+
+	for _, path := range []string{"/v1/items", "/v2/items", "/cursor/items"} {
+		t.Run(path, func(t *testing.T) {
+			atomic.StoreInt32(calls, 0)
+
+			items, err := FetchAllPages[restPageItem](context.Background(), client, PageRequest{Path: path, Concurrency: 3})
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, restPageItems(95), items, "Expect all items in order")
+			assert.Equal(t, int32(10), atomic.LoadInt32(calls), "Expect 10 pages fetched")
+		})
+	}
+}
+
+func TestFetchAllPagesMaxPages(t *testing.T) {
+	server, _ := newRestPageServer(t, 95, 0)
+	client := NewRestClient(server.URL)
+
+	for _, path := range []string{"/v2/items", "/cursor/items"} {
+		t.Run(path, func(t *testing.T) {
+			items, err := FetchAllPages[restPageItem](context.Background(), client, PageRequest{Path: path, MaxPages: 3})
+			assert.Equal(t, ErrMaxPagesExceeded, err, "Expect max pages error")
+			assert.Equal(t, restPageItems(30), items, "Expect items of fetched pages")
+		})
+	}
+}
+
+func TestStreamPagesError(t *testing.T) {
+	server, _ := newRestPageServer(t, 95, 4)
+	client := NewRestClient(server.URL)
+
+	pages := 0
+	var err error
+	for result := range StreamPages[restPageItem](context.Background(), client, PageRequest{Path: "/v2/items", Concurrency: 4}) {
+		if result.Err != nil {
+			err = result.Err
+			continue
+		}
+		pages++
+	}
+
+	assert.True(t, errors.Is(err, ErrUnauthorized), "Expect error of page 4")
+	assert.Equal(t, 3, pages, "Expect pages before the error")
+}
+
+func TestStreamPagesCancel(t *testing.T) {
+	server, calls := newRestPageServer(t, 95, 0)
+	client := NewRestClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pages := StreamPages[restPageItem](ctx, client, PageRequest{Path: "/v2/items"})
+
+	first := <-pages
+	assert.Nil(t, first.Err, "Expect first page")
+	cancel()
+
+	for range pages {
+	}
+	assert.Less(t, atomic.LoadInt32(calls), int32(10), "Expect fetching stop after cancel")
+}
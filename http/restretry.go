@@ -0,0 +1,263 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RetryConfig struct {
+	// MaxRetries is the max retries of a request, default is 3
+	MaxRetries int
+	// BaseDelay and MaxDelay bound the backoff, the delay is random between 0 and min(MaxDelay, BaseDelay * 2^attempt).
+	// Default is 100ms and 10s
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryNonIdempotent retry POST, PATCH and other non idempotent methods too
+	RetryNonIdempotent bool
+	// Budget limit the retries of all requests of the client, nil means no limit
+	Budget *RetryBudget
+}
+
+// RetryBudget limit retries to Ratio of the requests, so retries do not overload unhealthy service.
+// Burst is the retries allowed before any request is made and the max saved retries
+type RetryBudget struct {
+	Ratio float64
+	Burst int
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+func NewRetryBudget(ratio float64, burst int) *RetryBudget {
+	return &RetryBudget{Ratio: ratio, Burst: burst, tokens: float64(burst)}
+}
+
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.Ratio
+	if b.tokens > float64(b.Burst) {
+		b.tokens = float64(b.Burst)
+	}
+}
+
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+type retryKey struct{}
+
+// ContextWithRetry override the max retries of the request with the context, it also allow non idempotent
+// request to be retried (e.g. POST with idempotency key). Zero disable retry of the request
+func ContextWithRetry(ctx context.Context, maxRetries int) context.Context {
+	return context.WithValue(ctx, retryKey{}, maxRetries)
+}
+
+// retryTransport retry connection error, 5xx and 429 response. Retry-After of the response is honored,
+// the response is returned without retry when Retry-After is longer than MaxDelay
+type retryTransport struct {
+	next http.RoundTripper
+	conf RetryConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newRetryTransport(next http.RoundTripper, conf RetryConfig) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if conf.MaxRetries == 0 {
+		conf.MaxRetries = 3
+	}
+
+	if conf.BaseDelay <= 0 {
+		conf.BaseDelay = 100 * time.Millisecond
+	}
+
+	if conf.MaxDelay <= 0 {
+		conf.MaxDelay = 10 * time.Second
+	}
+
+	return &retryTransport{next: next, conf: conf, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxRetries := t.maxRetries(req)
+
+	if t.conf.Budget != nil {
+		t.conf.Budget.deposit()
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		res, err := t.next.RoundTrip(r)
+		if attempt >= maxRetries || ctx.Err() != nil || !retryable(res, err) {
+			return res, err
+		}
+
+		delay, ok := t.delay(res, attempt)
+		if !ok || (t.conf.Budget != nil && !t.conf.Budget.withdraw()) {
+			return res, err
+		}
+
+		if res != nil {
+			// drain the body so the connection can be reused
+			io.CopyN(io.Discard, res.Body, 4096)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (t *retryTransport) maxRetries(req *http.Request) int {
+	// request body that can not be rewound is sent once
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0
+	}
+
+	if maxRetries, ok := req.Context().Value(retryKey{}).(int); ok {
+		return maxRetries
+	}
+
+	if !t.conf.RetryNonIdempotent && !idempotentMethod(req.Method) {
+		return 0
+	}
+
+	return t.conf.MaxRetries
+}
+
+// delay return the Retry-After of the response or the full jitter backoff of the attempt
+func (t *retryTransport) delay(res *http.Response, attempt int) (time.Duration, bool) {
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= t.conf.MaxDelay
+		}
+	}
+
+	backoff := t.conf.MaxDelay
+	if attempt < 32 && t.conf.BaseDelay<<attempt > 0 && t.conf.BaseDelay<<attempt < backoff {
+		backoff = t.conf.BaseDelay << attempt
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return time.Duration(t.rand.Int63n(int64(backoff) + 1)), true
+}
+
+// maxEnvelopeSize is the max body read to find the V2 envelope status
+const maxEnvelopeSize = 64 << 10
+
+// retryable return whether the error or the response status is transient. V2 writer send error as 400 (500 for
+// unknown error) with the real status in the envelope, so the envelope status is used when the body is V2 envelope
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	status := res.StatusCode
+	if status == http.StatusBadRequest || status == http.StatusInternalServerError {
+		if envelopeStatus, ok := peekEnvelopeStatus(res); ok {
+			status = envelopeStatus
+		}
+	}
+
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// peekEnvelopeStatus return the status of V2 error envelope, the read body is put back to the response
+func peekEnvelopeStatus(res *http.Response) (int, bool) {
+	if res.Body == nil || res.Body == http.NoBody {
+		return 0, false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxEnvelopeSize))
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), res.Body), res.Body}
+	if err != nil {
+		return 0, false
+	}
+
+	envelope := struct {
+		Success *bool `json:"success"`
+		Status  int   `json:"status"`
+	}{}
+	if json.Unmarshal(data, &envelope) != nil || envelope.Success == nil || *envelope.Success || envelope.Status == 0 {
+		return 0, false
+	}
+
+	return envelope.Status, true
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parse Retry-After in seconds or http date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := time.Until(date)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
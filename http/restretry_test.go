@@ -0,0 +1,191 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRetryServer respond with status for the first failures calls, then 200
+func newRetryServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) > failures {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if status == 0 {
+			// close the connection without response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+	}))
+
+	return server, &calls
+}
+
+func TestRetry(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, 0} {
+		server, calls := newRetryServer(2, status, nil)
+		client := NewRestClient(server.URL, WithRetry(conf))
+
+		res, err := client.HttpClient.R().Get("/")
+		assert.Nil(t, err, "Expect no error after retry")
+		assert.Equal(t, http.StatusOK, res.StatusCode(), "Expect success after retry")
+		assert.Equal(t, int32(3), atomic.LoadInt32(calls), "Expect 2 retries")
+		server.Close()
+	}
+
+	server, calls := newRetryServer(5, http.StatusBadGateway, nil)
+	defer server.Close()
+	res, _ := NewRestClient(server.URL, WithRetry(conf)).HttpClient.R().Get("/")
+	assert.Equal(t, http.StatusBadGateway, res.StatusCode(), "Expect last response after max retries")
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls), "Expect default 3 retries")
+
+	server, calls = newRetryServer(1, http.StatusBadRequest, nil)
+	defer server.Close()
+	res, _ = NewRestClient(server.URL, WithRetry(conf)).HttpClient.R().Get("/")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode(), "Expect 4xx is not retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls), "Expect no retry")
+}
+
+func TestRetryEnvelopeStatus(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	tests := []struct {
+		name  string
+		err   error
+		calls int32
+	}{
+		{name: "service unhealthy", err: ErrServiceUnhealthy, calls: 3},
+		{name: "too many requests", err: ErrTooManyRequests, calls: 3},
+		{name: "unknown error", err: errors.New("database is down"), calls: 3},
+		{name: "unauthorized", err: ErrUnauthorized, calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+				if atomic.AddInt32(&calls, 1) <= 2 {
+					return HttpHandleResultV2{Error: tt.err}
+				}
+				return HttpHandleResultV2{Data: restDecodeUser{ID: 1}}
+			}))
+			defer server.Close()
+
+			_, err := Call[restDecodeUser](context.Background(), NewRestClient(server.URL, WithRetry(conf)), http.MethodGet, "/", nil)
+			assert.Equal(t, tt.calls, atomic.LoadInt32(&calls), "Expect calls")
+			if tt.calls == 1 {
+				assert.True(t, errors.Is(err, ErrUnauthorized), "Expect the envelope body is decoded after peek")
+			} else {
+				assert.Nil(t, err, "Expect success after retry")
+			}
+		})
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	server, calls := newRetryServer(1, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+	client := NewRestClient(server.URL, WithRetry(conf))
+
+	res, _ := client.HttpClient.R().SetBody(map[string]string{"name": "agung"}).Post("/")
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode(), "Expect POST is not retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls), "Expect no retry")
+
+	atomic.StoreInt32(calls, 0)
+	ctx := ContextWithRetry(context.Background(), 1)
+	res, _ = client.HttpClient.R().SetContext(ctx).SetBody(map[string]string{"name": "agung"}).Post("/")
+	assert.Equal(t, http.StatusOK, res.StatusCode(), "Expect POST is retried with the request override")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls), "Expect 1 retry")
+
+	atomic.StoreInt32(calls, 0)
+	res, _ = client.HttpClient.R().SetContext(ContextWithRetry(context.Background(), 0)).Get("/")
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode(), "Expect retry is disabled by the request override")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls), "Expect no retry")
+}
+
+func TestRetryAfter(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+	server, calls := newRetryServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	defer server.Close()
+
+	start := time.Now()
+	res, _ := NewRestClient(server.URL, WithRetry(conf)).HttpClient.R().Get("/")
+	assert.Equal(t, http.StatusOK, res.StatusCode(), "Expect success after Retry-After")
+	assert.True(t, time.Since(start) >= time.Second, "Expect to wait Retry-After")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls), "Expect 1 retry")
+
+	server, calls = newRetryServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+	defer server.Close()
+	res, _ = NewRestClient(server.URL, WithRetry(conf)).HttpClient.R().Get("/")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode(), "Expect Retry-After longer than MaxDelay is not retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls), "Expect no retry")
+}
+
+func TestRetryBudget(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Budget: NewRetryBudget(0.5, 1)}
+
+	server, calls := newRetryServer(100, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+	client := NewRestClient(server.URL, WithRetry(conf))
+
+	client.HttpClient.R().Get("/")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls), "Expect the burst retry")
+
+	client.HttpClient.R().Get("/")
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls), "Expect no retry when budget is exhausted")
+
+	client.HttpClient.R().Get("/")
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls), "Expect retry after the requests refill the budget")
+}
+
+func TestRetryCancel(t *testing.T) {
+	conf := RetryConfig{BaseDelay: time.Second, MaxDelay: time.Second}
+
+	server, calls := newRetryServer(100, http.StatusServiceUnavailable, http.Header{"Retry-After": {"1"}})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewRestClient(server.URL, WithRetry(conf)).HttpClient.R().SetContext(ctx).Get("/")
+	assert.NotNil(t, err, "Expect context error")
+	assert.True(t, time.Since(start) < time.Second, "Expect the wait is cancelled")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls), "Expect no retry after cancel")
+}
+
+func TestRetryBackoff(t *testing.T) {
+	transport := newRetryTransport(nil, RetryConfig{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond})
+
+	for attempt := 0; attempt < 40; attempt++ {
+		limit := 50 * time.Millisecond
+		if attempt < 3 {
+			limit = (10 * time.Millisecond) << attempt
+		}
+
+		delay, ok := transport.delay(nil, attempt)
+		assert.True(t, ok, "Expect retry")
+		assert.True(t, delay >= 0 && delay <= limit, "Expect full jitter delay of attempt %d is between 0 and %s", attempt, limit)
+	}
+}
@@ -8,8 +8,24 @@ type RestClient struct {
 	HttpClient *resty.Client
 }
 
-func NewRestClient(baseUrl string) *RestClient {
+type RestClientOption func(*RestClient)
+
+// WithRetry retry failed requests with exponential backoff and full jitter, see RetryConfig
+func WithRetry(conf RetryConfig) RestClientOption {
+	return func(c *RestClient) {
+		c.HttpClient.SetTransport(newRetryTransport(c.HttpClient.GetClient().Transport, conf))
+	}
+}
+
+func NewRestClient(baseUrl string, opts ...RestClientOption) *RestClient {
 	httpClient := resty.New()
 	httpClient.SetHostURL(baseUrl)
-	return &RestClient{HttpClient: httpClient}
+	instrumentTracing(httpClient)
+
+	c := &RestClient{HttpClient: httpClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// SecureCookie create cookie with secure defaults: HttpOnly, Secure, SameSite=Lax and Path=/,
+// zero maxAge create session cookie
+func SecureCookie(name string, value string, maxAge time.Duration) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if maxAge > 0 {
+		cookie.MaxAge = int(maxAge.Seconds())
+		cookie.Expires = time.Now().Add(maxAge)
+	}
+
+	return cookie
+}
+
+// ExpiredCookie create cookie that delete the cookie with the name on the client
+func ExpiredCookie(name string) *http.Cookie {
+	cookie := SecureCookie(name, "", 0)
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
+
+	return cookie
+}
+
+// ErrorResultV2 create result of err, error with dynamic message that wrap registered error (e.g. error of GetSort)
+// is written as the registered error with the dynamic message
+func ErrorResultV2(err error) HttpHandleResultV2 {
+	result := HttpHandleResultV2{Error: err}
+
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) && error(errorResponse) != err {
+		result.Error = errorResponse
+		result.Message = []string{err.Error()}
+	}
+
+	return result
+}
+
+// SetHeader set response header, it is applied before the body is written
+func (res *HttpHandleResult) SetHeader(key string, value string) {
+	if res.Headers == nil {
+		res.Headers = http.Header{}
+	}
+	res.Headers.Set(key, value)
+}
+
+// AddCookie add Set-Cookie to the response with HttpOnly and Secure enabled, empty Path default to "/" and
+// SameSite default to Lax
+func (res *HttpHandleResult) AddCookie(cookie *http.Cookie) {
+	res.Cookies = append(res.Cookies, cookie)
+}
+
+// AddInsecureCookie add Set-Cookie to the response with HttpOnly and Secure as set by the caller (e.g. cookie read by
+// javascript or sent over plain http in development), empty Path default to "/" and SameSite default to Lax
+func (res *HttpHandleResult) AddInsecureCookie(cookie *http.Cookie) {
+	res.InsecureCookies = append(res.InsecureCookies, cookie)
+}
+
+// SetRedirect redirect the client to url instead of writing the envelope, statusCode other than 301, 302, 303, 307
+// and 308 is replaced with 302
+func (res *HttpHandleResult) SetRedirect(url string, statusCode int) {
+	res.RedirectURL = url
+	res.StatusCode = statusCode
+}
+
+// SetHeader set response header, it is applied before the body is written
+func (res *HttpHandleResultV2) SetHeader(key string, value string) {
+	if res.Headers == nil {
+		res.Headers = http.Header{}
+	}
+	res.Headers.Set(key, value)
+}
+
+// AddCookie add Set-Cookie to the response with HttpOnly and Secure enabled, empty Path default to "/" and
+// SameSite default to Lax
+func (res *HttpHandleResultV2) AddCookie(cookie *http.Cookie) {
+	res.Cookies = append(res.Cookies, cookie)
+}
+
+// AddInsecureCookie add Set-Cookie to the response with HttpOnly and Secure as set by the caller (e.g. cookie read by
+// javascript or sent over plain http in development), empty Path default to "/" and SameSite default to Lax
+func (res *HttpHandleResultV2) AddInsecureCookie(cookie *http.Cookie) {
+	res.InsecureCookies = append(res.InsecureCookies, cookie)
+}
+
+// SetRedirect redirect the client to url instead of writing the envelope, statusCode other than 301, 302, 303, 307
+// and 308 is replaced with 302
+func (res *HttpHandleResultV2) SetRedirect(url string, statusCode int) {
+	res.RedirectURL = url
+	res.StatusCode = statusCode
+}
+
+// writeResultHeaders apply the result headers and cookies, it must be called before WriteHeader
+func writeResultHeaders(w http.ResponseWriter, headers http.Header, cookies []*http.Cookie, insecureCookies []*http.Cookie) {
+	dst := w.Header()
+	for key, values := range headers {
+		dst.Del(key)
+		for _, value := range values {
+			dst.Add(key, value)
+		}
+	}
+
+	for _, c := range cookies {
+		cookie := *c
+		cookie.HttpOnly = true
+		cookie.Secure = true
+		setCookie(w, cookie)
+	}
+
+	for _, c := range insecureCookies {
+		setCookie(w, *c)
+	}
+}
+
+func setCookie(w http.ResponseWriter, cookie http.Cookie) {
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	http.SetCookie(w, &cookie)
+}
+
+func redirectStatus(statusCode int) int {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return statusCode
+	}
+
+	return http.StatusFound
+}
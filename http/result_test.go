@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerResultHeadersAndCookies(t *testing.T) {
+	newHandler := NewHttpHandlerV2(NewContextHandlerV2(false))
+
+	handler := newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		response.SetHeader("X-Request-Id", "abc")
+		response.SetHeader("Cache-Control", "no-store")
+		response.AddCookie(SecureCookie("session", "token", time.Hour))
+		response.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		response.AddInsecureCookie(&http.Cookie{Name: "csrf", Value: "abc"})
+		response.Data = "OK"
+
+		return
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.Equal(t, "abc", w.Header().Get("X-Request-Id"), "Expect custom header")
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"), "Expect custom header")
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"), "Expect JSON content type")
+
+	cookies := w.Result().Cookies()
+	if assert.Len(t, cookies, 3, "Expect 3 cookies") {
+		assert.Equal(t, "session", cookies[0].Name, "Expect session cookie")
+		assert.True(t, cookies[0].HttpOnly, "Expect HttpOnly")
+		assert.True(t, cookies[0].Secure, "Expect Secure")
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite, "Expect SameSite=Lax")
+		assert.Equal(t, 3600, cookies[0].MaxAge, "Expect max age")
+
+		assert.Equal(t, "/", cookies[1].Path, "Expect default path")
+		assert.Equal(t, http.SameSiteLaxMode, cookies[1].SameSite, "Expect default SameSite")
+		assert.True(t, cookies[1].HttpOnly, "Expect default HttpOnly")
+		assert.True(t, cookies[1].Secure, "Expect default Secure")
+
+		assert.Equal(t, "csrf", cookies[2].Name, "Expect insecure cookie")
+		assert.False(t, cookies[2].HttpOnly, "Expect insecure cookie without HttpOnly")
+		assert.False(t, cookies[2].Secure, "Expect insecure cookie without Secure")
+		assert.Equal(t, http.SameSiteLaxMode, cookies[2].SameSite, "Expect default SameSite of insecure cookie")
+	}
+}
+
+func TestHandlerResultHeadersOnError(t *testing.T) {
+	newHandler := NewHttpHandler(NewContextHandler(false))
+
+	handler := newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResult) {
+		response.AddCookie(ExpiredCookie("session"))
+		response.Error = ErrUnauthorized
+
+		return
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Expect 401 status code")
+	cookies := w.Result().Cookies()
+	if assert.Len(t, cookies, 1, "Expect 1 cookie") {
+		assert.Equal(t, -1, cookies[0].MaxAge, "Expect cookie is deleted")
+	}
+}
+
+func TestHandlerResultRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		expected   int
+	}{
+		{name: "default", expected: http.StatusFound},
+		{name: "permanent", statusCode: http.StatusMovedPermanently, expected: http.StatusMovedPermanently},
+		{name: "see other", statusCode: http.StatusSeeOther, expected: http.StatusSeeOther},
+		{name: "temporary", statusCode: http.StatusTemporaryRedirect, expected: http.StatusTemporaryRedirect},
+		{name: "permanent redirect", statusCode: http.StatusPermanentRedirect, expected: http.StatusPermanentRedirect},
+		{name: "non redirect status", statusCode: http.StatusOK, expected: http.StatusFound},
+		{name: "not modified", statusCode: http.StatusNotModified, expected: http.StatusFound},
+		{name: "use proxy", statusCode: http.StatusUseProxy, expected: http.StatusFound},
+		{name: "error take precedence", err: errors.New("failed"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerV1 := NewHttpHandler(NewContextHandler(false))(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResult) {
+				response.SetRedirect("/login", tt.statusCode)
+				response.Error = tt.err
+				return
+			})
+
+			handlerV2 := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+				response.SetRedirect("/login", tt.statusCode)
+				response.Error = tt.err
+				return
+			})
+
+			w := httptest.NewRecorder()
+			handlerV1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+			assert.Equal(t, tt.expected, w.Code, "Expect V1 status code")
+			if tt.err == nil {
+				assert.Equal(t, "/login", w.Header().Get("Location"), "Expect V1 location")
+			}
+
+			w = httptest.NewRecorder()
+			handlerV2.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+			if tt.err == nil {
+				assert.Equal(t, tt.expected, w.Code, "Expect V2 status code")
+				assert.Equal(t, "/login", w.Header().Get("Location"), "Expect V2 location")
+			} else {
+				assert.Empty(t, w.Header().Get("Location"), "Expect no V2 redirect")
+			}
+		})
+	}
+}
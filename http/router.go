@@ -0,0 +1,287 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RouteInfo is the registered route metadata, Pattern is the route template (e.g. /users/{id})
+type RouteInfo struct {
+	Method  string
+	Pattern string
+}
+
+type routeContextKey struct{}
+
+type routeContext struct {
+	RouteInfo
+	params map[string]string
+}
+
+type routeSegment struct {
+	value    string
+	isParam  bool
+	isCatch  bool
+	paramKey string
+}
+
+type route struct {
+	RouteInfo
+	segments []routeSegment
+	handler  http.Handler
+}
+
+// Router is simple method + pattern router for golib handlers, pattern segment can be static, {name} for path parameter
+// or {name...} to match the rest of the path. Not found and method not allowed are written in V2 envelope.
+type Router struct {
+	CustomWriterV2
+	routes      []*route
+	middlewares []func(http.Handler) http.Handler
+	// handler is the middleware chain, it is built when middleware is added
+	handler http.Handler
+}
+
+func NewRouter(c HandlerContextV2) *Router {
+	return &Router{CustomWriterV2: CustomWriterV2{C: c}}
+}
+
+// Use add middleware that run for every request, including not found and method not allowed.
+// It must be called before the router serve requests
+func (rt *Router) Use(middlewares ...func(http.Handler) http.Handler) {
+	rt.middlewares = append(rt.middlewares, middlewares...)
+
+	var handler http.Handler = http.HandlerFunc(rt.dispatch)
+	for i := len(rt.middlewares) - 1; i >= 0; i-- {
+		handler = rt.middlewares[i](handler)
+	}
+	rt.handler = handler
+}
+
+func (rt *Router) Handle(method string, pattern string, handler http.Handler) {
+	r := &route{
+		RouteInfo: RouteInfo{Method: strings.ToUpper(method), Pattern: pattern},
+		handler:   handler,
+	}
+
+	for _, s := range splitPath(pattern) {
+		segment := routeSegment{value: s}
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			segment.isParam = true
+			segment.paramKey = s[1 : len(s)-1]
+			if strings.HasSuffix(segment.paramKey, "...") {
+				segment.isCatch = true
+				segment.paramKey = strings.TrimSuffix(segment.paramKey, "...")
+			}
+		}
+		r.segments = append(r.segments, segment)
+	}
+
+	rt.routes = append(rt.routes, r)
+}
+
+func (rt *Router) Get(pattern string, handler http.Handler) {
+	rt.Handle(http.MethodGet, pattern, handler)
+}
+
+func (rt *Router) Post(pattern string, handler http.Handler) {
+	rt.Handle(http.MethodPost, pattern, handler)
+}
+
+func (rt *Router) Put(pattern string, handler http.Handler) {
+	rt.Handle(http.MethodPut, pattern, handler)
+}
+
+func (rt *Router) Patch(pattern string, handler http.Handler) {
+	rt.Handle(http.MethodPatch, pattern, handler)
+}
+
+func (rt *Router) Delete(pattern string, handler http.Handler) {
+	rt.Handle(http.MethodDelete, pattern, handler)
+}
+
+// Routes return all registered routes
+func (rt *Router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(rt.routes))
+	for _, r := range rt.routes {
+		routes = append(routes, r.RouteInfo)
+	}
+
+	return routes
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rt.handler == nil {
+		rt.dispatch(w, r)
+		return
+	}
+
+	rt.handler.ServeHTTP(w, r)
+}
+
+func (rt *Router) dispatch(w http.ResponseWriter, r *http.Request) {
+	path := splitPath(r.URL.Path)
+
+	var matched, matchedGet *route
+	var matchedParams, matchedGetParams map[string]string
+	matchedScore, matchedGetScore := -1, -1
+	allowed := []string{}
+
+	for _, rr := range rt.routes {
+		params, score, ok := rr.match(path)
+		if !ok {
+			continue
+		}
+
+		if rr.Method != r.Method {
+			allowed = appendUnique(allowed, rr.Method)
+			if rr.Method == http.MethodGet {
+				allowed = appendUnique(allowed, http.MethodHead)
+
+				// HEAD is served by GET route when there is no HEAD route, like net/http
+				if r.Method == http.MethodHead && score > matchedGetScore {
+					matchedGet, matchedGetParams, matchedGetScore = rr, params, score
+				}
+			}
+			continue
+		}
+
+		if score > matchedScore {
+			matched, matchedParams, matchedScore = rr, params, score
+		}
+	}
+
+	if matched == nil && matchedGet != nil {
+		matched, matchedParams = matchedGet, matchedGetParams
+	}
+
+	if matched == nil {
+		if len(allowed) > 0 {
+			sort.Strings(allowed)
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			setRequestCode(r, ErrMethodNotAllowed.HttpStatus)
+			rt.WriteError(w, ErrMethodNotAllowed, nil)
+			return
+		}
+
+		setRequestCode(r, ErrRouteNotFound.HttpStatus)
+		rt.WriteError(w, ErrRouteNotFound, nil)
+		return
+	}
+
+	setRequestRoute(r, matched.Pattern)
+	ctx := context.WithValue(r.Context(), routeContextKey{}, &routeContext{
+		RouteInfo: matched.RouteInfo,
+		params:    matchedParams,
+	})
+	matched.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// match return the path params and score, static segment have higher score than param segment
+func (rr *route) match(path []string) (params map[string]string, score int, ok bool) {
+	params = map[string]string{}
+
+	for i, segment := range rr.segments {
+		if segment.isCatch {
+			params[segment.paramKey] = strings.Join(path[i:], "/")
+			return params, score, true
+		}
+
+		if i >= len(path) {
+			return nil, 0, false
+		}
+
+		if segment.isParam {
+			params[segment.paramKey] = path[i]
+			score++
+			continue
+		}
+
+		if segment.value != path[i] {
+			return nil, 0, false
+		}
+		score += 2
+	}
+
+	if len(path) != len(rr.segments) {
+		return nil, 0, false
+	}
+
+	return params, score, true
+}
+
+// WithRoute record the route template for handler registered on other router, so RoutePattern works for logging and metrics
+func WithRoute(method string, pattern string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(routeContextKey{}).(*routeContext); ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		setRequestRoute(r, pattern)
+		ctx := context.WithValue(r.Context(), routeContextKey{}, &routeContext{
+			RouteInfo: RouteInfo{Method: method, Pattern: pattern},
+			params:    map[string]string{},
+		})
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RoutePattern return the matched route template, empty if the request is not routed by Router or WithRoute
+func RoutePattern(r *http.Request) string {
+	if rc, ok := r.Context().Value(routeContextKey{}).(*routeContext); ok {
+		return rc.Pattern
+	}
+
+	return ""
+}
+
+// PathParam return the path parameter value, empty if not exist
+func PathParam(r *http.Request, name string) string {
+	if rc, ok := r.Context().Value(routeContextKey{}).(*routeContext); ok {
+		return rc.params[name]
+	}
+
+	return ""
+}
+
+// PathParamInt return the path parameter as int, ErrInvalidPathParam if it is not a number
+func PathParamInt(r *http.Request, name string) (int, error) {
+	value, err := strconv.Atoi(PathParam(r, name))
+	if err != nil {
+		return 0, ErrInvalidPathParam
+	}
+
+	return value, nil
+}
+
+// PathParamInt64 return the path parameter as int64, ErrInvalidPathParam if it is not a number
+func PathParamInt64(r *http.Request, name string) (int64, error) {
+	value, err := strconv.ParseInt(PathParam(r, name), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidPathParam
+	}
+
+	return value, nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, "/")
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
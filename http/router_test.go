@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	router := NewRouter(handlerCtx)
+
+	router.Get("/users/{id}", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		id, err := PathParamInt(r, "id")
+		if err != nil {
+			response.Error = err
+			return
+		}
+
+		response.Data = map[string]interface{}{"id": id, "route": RoutePattern(r)}
+		return
+	}))
+	router.Get("/users/me", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		response.Data = map[string]interface{}{"route": RoutePattern(r)}
+		return
+	}))
+	router.Delete("/users/{id}", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		return
+	}))
+	router.Get("/files/{path...}", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		response.Data = PathParam(r, "path")
+		return
+	}))
+
+	request := func(method string, target string) (*httptest.ResponseRecorder, ResponseV2) {
+		req := httptest.NewRequest(method, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		respJson := ResponseV2{}
+		_ = json.Unmarshal(w.Body.Bytes(), &respJson)
+		return w, respJson
+	}
+
+	_, resp := request(http.MethodGet, "/users/10")
+	assert.Equal(t, map[string]interface{}{"id": float64(10), "route": "/users/{id}"}, resp.Data, "Expect path param and route template")
+
+	_, resp = request(http.MethodGet, "/users/me/")
+	assert.Equal(t, map[string]interface{}{"route": "/users/me"}, resp.Data, "Expect static route preferred")
+
+	_, resp = request(http.MethodGet, "/users/abc")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "Expect 400 status code in body")
+	assert.Equal(t, []string{ErrInvalidPathParam.ResponseDesc}, resp.Message, "Expect invalid path param message")
+
+	_, resp = request(http.MethodGet, "/files/a/b/c.txt")
+	assert.Equal(t, "a/b/c.txt", resp.Data, "Expect catch all path param")
+
+	_, resp = request(http.MethodGet, "/orders")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "Expect 404 status code in body")
+	assert.Equal(t, false, resp.Success, "Expect Success False")
+
+	w, resp := request(http.MethodPost, "/users/10")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode, "Expect 405 status code in body")
+	assert.Equal(t, "DELETE, GET, HEAD", w.Header().Get("Allow"), "Expect allow header with HEAD of GET route")
+
+	w, resp = request(http.MethodHead, "/users/me")
+	assert.Equal(t, http.StatusOK, w.Code, "Expect HEAD served by GET route")
+	assert.Equal(t, map[string]interface{}{"route": "/users/me"}, resp.Data, "Expect the most specific GET route")
+
+	assert.Equal(t, []RouteInfo{
+		{Method: http.MethodGet, Pattern: "/users/{id}"},
+		{Method: http.MethodGet, Pattern: "/users/me"},
+		{Method: http.MethodDelete, Pattern: "/users/{id}"},
+		{Method: http.MethodGet, Pattern: "/files/{path...}"},
+	}, router.Routes(), "Expect registered routes")
+}
+
+func TestWithRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/health", WithRoute(http.MethodGet, "/health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(RoutePattern(r)))
+	})))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, "/health", w.Body.String(), "Expect route template recorded")
+}
+
+func TestRouterMiddlewareChain(t *testing.T) {
+	router := NewRouter(NewContextHandlerV2(false))
+
+	built := 0
+	order := []string{}
+	middleware := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			built++
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	router.Use(middleware("first"))
+	router.Use(middleware("second"))
+	router.Get("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	built = 0
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, w.Code, "Expect route handled")
+	}
+
+	assert.Equal(t, 0, built, "Expect middleware chain is not rebuilt per request")
+	assert.Equal(t, []string{"first", "second"}, order[:2], "Expect middleware order")
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type ServerConfig struct {
+	Addr string
+	// ReadTimeout default is 30 seconds
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout default is 10 seconds
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout default is 30 seconds
+	WriteTimeout time.Duration
+	// IdleTimeout default is 120 seconds
+	IdleTimeout time.Duration
+	// MaxHeaderBytes default is 1 MB
+	MaxHeaderBytes int
+	// DrainPeriod is how long the server keep serving after it is marked not ready,
+	// so load balancer can stop sending new request before the listener is closed
+	DrainPeriod time.Duration
+	// ShutdownTimeout is how long to wait in-flight request and shutdown hooks, default is 30 seconds
+	ShutdownTimeout time.Duration
+}
+
+type shutdownHook struct {
+	name string
+	hook func(ctx context.Context) error
+}
+
+// Server is http server with graceful shutdown on SIGINT/SIGTERM
+type Server struct {
+	CustomWriterV2
+	Config  ServerConfig
+	Handler http.Handler
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+	ready int32
+}
+
+func NewServer(c HandlerContextV2, handler http.Handler, conf ServerConfig) *Server {
+	if conf.ReadTimeout == 0 {
+		conf.ReadTimeout = 30 * time.Second
+	}
+
+	if conf.ReadHeaderTimeout == 0 {
+		conf.ReadHeaderTimeout = 10 * time.Second
+	}
+
+	if conf.WriteTimeout == 0 {
+		conf.WriteTimeout = 30 * time.Second
+	}
+
+	if conf.IdleTimeout == 0 {
+		conf.IdleTimeout = 120 * time.Second
+	}
+
+	if conf.MaxHeaderBytes == 0 {
+		conf.MaxHeaderBytes = http.DefaultMaxHeaderBytes
+	}
+
+	if conf.ShutdownTimeout == 0 {
+		conf.ShutdownTimeout = 30 * time.Second
+	}
+
+	return &Server{
+		CustomWriterV2: CustomWriterV2{C: c},
+		Config:         conf,
+		Handler:        handler,
+	}
+}
+
+// OnShutdown register hook (close DB, flush logs) that run after the server stop accepting request,
+// hooks run in reverse order of registration
+func (s *Server) OnShutdown(name string, hook func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, shutdownHook{name: name, hook: hook})
+}
+
+// IsReady return false before the server is listening and during shutdown
+func (s *Server) IsReady() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+// ReadinessHandler return ErrServerNotReady with 503 http status when the server is not ready
+func (s *Server) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.IsReady() {
+			s.writeErrorWithStatus(w, ErrServerNotReady, nil, http.StatusServiceUnavailable)
+			return
+		}
+
+		s.Write(w, nil, http.StatusOK, nil, []string{"ready"})
+	})
+}
+
+// ListenAndServe listen on Config.Addr and block until SIGINT/SIGTERM is received and the server is shut down
+func (s *Server) ListenAndServe() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return s.Run(ctx)
+}
+
+// Run listen on Config.Addr and block until ctx is done and the server is shut down
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Config.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, ln)
+}
+
+// Serve accept connection on the listener and block until ctx is done and the server is shut down
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	server := &http.Server{
+		Handler:           s.Handler,
+		ReadTimeout:       s.Config.ReadTimeout,
+		ReadHeaderTimeout: s.Config.ReadHeaderTimeout,
+		WriteTimeout:      s.Config.WriteTimeout,
+		IdleTimeout:       s.Config.IdleTimeout,
+		MaxHeaderBytes:    s.Config.MaxHeaderBytes,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(ln)
+	}()
+
+	atomic.StoreInt32(&s.ready, 1)
+	log.Logger.Info().Msgf("server listening on %s", ln.Addr())
+
+	select {
+	case err := <-serveErr:
+		atomic.StoreInt32(&s.ready, 0)
+		s.runHooks(context.Background())
+		return err
+	case <-ctx.Done():
+	}
+
+	return s.shutdown(server)
+}
+
+func (s *Server) shutdown(server *http.Server) error {
+	atomic.StoreInt32(&s.ready, 0)
+	log.Logger.Info().Msgf("server shutting down, draining for %s", s.Config.DrainPeriod)
+	time.Sleep(s.Config.DrainPeriod)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.Config.ShutdownTimeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("error on shutdown server")
+	}
+
+	hookCtx, hookCancel := context.WithTimeout(context.Background(), s.Config.ShutdownTimeout)
+	defer hookCancel()
+
+	if hookErr := s.runHooks(hookCtx); err == nil {
+		err = hookErr
+	}
+
+	log.Logger.Info().Msg("server stopped")
+	return err
+}
+
+// runHooks run all shutdown hooks and return the first error
+func (s *Server) runHooks(ctx context.Context) (err error) {
+	s.mu.Lock()
+	hooks := append([]shutdownHook{}, s.hooks...)
+	s.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hookErr := hooks[i].hook(ctx); hookErr != nil {
+			log.Logger.Error().Err(hookErr).Msgf("error on shutdown hook %s", hooks[i].name)
+			if err == nil {
+				err = hookErr
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerGracefulShutdown(t *testing.T) {
+	handlerCtx := NewContextHandlerV2(false)
+	router := NewRouter(handlerCtx)
+
+	server := NewServer(handlerCtx, router, ServerConfig{DrainPeriod: 100 * time.Millisecond})
+	router.Get("/readyz", server.ReadinessHandler())
+
+	hooks := []string{}
+	server.OnShutdown("db", func(ctx context.Context) error {
+		hooks = append(hooks, "db")
+		return nil
+	})
+	server.OnShutdown("logger", func(ctx context.Context) error {
+		hooks = append(hooks, "logger")
+		return errors.New("flush failed")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- server.Serve(ctx, ln)
+	}()
+
+	readiness := func() (ResponseV2, int) {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/readyz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		respJson := ResponseV2{}
+		_ = json.NewDecoder(resp.Body).Decode(&respJson)
+		return respJson, resp.StatusCode
+	}
+
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond, "Expect server ready")
+	resp, httpStatus := readiness()
+	assert.Equal(t, http.StatusOK, httpStatus, "Expect ready")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Expect ready status in body")
+
+	cancel()
+	assert.Eventually(t, func() bool { return !server.IsReady() }, time.Second, 10*time.Millisecond, "Expect server not ready")
+
+	// still serving during drain period
+	resp, httpStatus = readiness()
+	assert.Equal(t, http.StatusServiceUnavailable, httpStatus, "Expect 503 http status")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "Expect 503 status code in body")
+	assert.Equal(t, []string{ErrServerNotReady.ResponseDesc}, resp.Message, "Expect not ready message")
+
+	err = <-done
+	assert.EqualError(t, err, "flush failed", "Expect hook error returned")
+	assert.Equal(t, []string{"logger", "db"}, hooks, "Expect hooks run in reverse order")
+}
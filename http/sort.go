@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SortField is validated sort field, Name is the mapped column name
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// SortAllowlist map the sort name accepted from client to column name, e.g. {"created_at": "u.created_at"}
+type SortAllowlist map[string]string
+
+// Fields return the allowed sort names in alphabetical order
+func (a SortAllowlist) Fields() []string {
+	fields := make([]string, 0, len(a))
+	for field := range a {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// ParseSort parse comma separated sort like "-created_at,name" or "created_at:desc,name:asc" into SortField,
+// field that is not in allowlist or invalid direction return 400 error response listing the allowed fields
+func ParseSort(value string, allowlist SortAllowlist) ([]SortField, error) {
+	fields := []SortField{}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, desc, ok := parseSortPart(part)
+		if !ok {
+			return nil, invalidSortError(allowlist)
+		}
+
+		column, ok := allowlist[name]
+		if !ok {
+			return nil, invalidSortError(allowlist)
+		}
+
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		fields = append(fields, SortField{Name: column, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// SortFields parse the sort of the request pagination, defaultSort is returned when client does not send sort
+func (p RequestPagination) SortFields(allowlist SortAllowlist, defaultSort ...SortField) ([]SortField, error) {
+	fields, err := ParseSort(strings.Join(p.Sort, ","), allowlist)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return defaultSort, nil
+	}
+
+	return fields, nil
+}
+
+// GetSort parse the sort query field of the request
+func GetSort(r *http.Request, field string, allowlist SortAllowlist) ([]SortField, error) {
+	return ParseSort(r.URL.Query().Get(field), allowlist)
+}
+
+func parseSortPart(part string) (name string, desc bool, ok bool) {
+	if name, direction, found := strings.Cut(part, ":"); found {
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "asc":
+			return strings.TrimSpace(name), false, true
+		case "desc":
+			return strings.TrimSpace(name), true, true
+		default:
+			return "", false, false
+		}
+	}
+
+	if strings.HasPrefix(part, "-") {
+		return part[1:], true, true
+	}
+
+	return strings.TrimPrefix(part, "+"), false, true
+}
+
+// invalidSortError wrap ErrInvalidSort with the allowed fields, so errors.Is(err, ErrInvalidSort) is true
+func invalidSortError(allowlist SortAllowlist) error {
+	return fmt.Errorf("%w, allowed fields: %s", ErrInvalidSort, strings.Join(allowlist.Fields(), ", "))
+}
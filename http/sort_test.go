@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSortAllowlist = SortAllowlist{
+	"created_at": "u.created_at",
+	"name":       "u.name",
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []SortField
+		err      bool
+	}{
+		{name: "empty", value: "", expected: []SortField{}},
+		{name: "prefix", value: "-created_at,name", expected: []SortField{{Name: "u.created_at", Desc: true}, {Name: "u.name"}}},
+		{name: "plus prefix", value: "+name", expected: []SortField{{Name: "u.name"}}},
+		{name: "direction", value: "created_at:desc,name:ASC", expected: []SortField{{Name: "u.created_at", Desc: true}, {Name: "u.name"}}},
+		{name: "space and empty part", value: " name , ,-created_at ", expected: []SortField{{Name: "u.name"}, {Name: "u.created_at", Desc: true}}},
+		{name: "duplicate", value: "name,-name", expected: []SortField{{Name: "u.name"}}},
+		{name: "not allowed", value: "password", err: true},
+		{name: "column name is not accepted", value: "u.name", err: true},
+		{name: "invalid direction", value: "name:up", err: true},
+		{name: "injection", value: "name;drop table users", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseSort(tt.value, testSortAllowlist)
+			if tt.err {
+				assert.True(t, errors.Is(err, ErrInvalidSort), "Expect ErrInvalidSort")
+				assert.Equal(t, "Invalid sort field, allowed fields: created_at, name", err.Error(), "Expect allowed fields in message")
+				return
+			}
+
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, tt.expected, fields, "Expect sort fields")
+		})
+	}
+}
+
+func TestRequestPaginationSortFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?order=-created_at,name", nil)
+	reqPage := GetPagination(req, 10, PaginationFields{SortField: "order"})
+
+	fields, err := reqPage.SortFields(testSortAllowlist)
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, []SortField{{Name: "u.created_at", Desc: true}, {Name: "u.name"}}, fields, "Expect sort fields")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	reqPage = GetPagination(req, 10, PaginationFields{SortField: "order"})
+	fields, err = reqPage.SortFields(testSortAllowlist, SortField{Name: "u.created_at", Desc: true})
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, []SortField{{Name: "u.created_at", Desc: true}}, fields, "Expect default sort")
+}
+
+func TestInvalidSortResponse(t *testing.T) {
+	handler := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		fields, err := GetSort(r, "sort", testSortAllowlist)
+		if err != nil {
+			return ErrorResultV2(err)
+		}
+
+		return HttpHandleResultV2{Data: fields}
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?sort=password", nil))
+
+	resp := ResponseV2{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expect 400 http status")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "Expect 400 status in body")
+	assert.Equal(t, []string{"Invalid sort field, allowed fields: created_at, name"}, resp.Message, "Expect allowed fields in message")
+}
+
+func TestInvalidSortErrorReturnedDirectly(t *testing.T) {
+	handlerV2 := NewHttpHandlerV2(NewContextHandlerV2(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		_, err := GetSort(r, "sort", testSortAllowlist)
+		return HttpHandleResultV2{Error: err}
+	})
+
+	w := httptest.NewRecorder()
+	handlerV2.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?sort=password", nil))
+
+	resp := ResponseV2{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expect 400 http status")
+	assert.Equal(t, ErrInvalidSort.HttpStatus, resp.StatusCode, "Expect wrapped error is looked up")
+	assert.Equal(t, []string{ErrInvalidSort.ResponseDesc}, resp.Message, "Expect registered message")
+
+	handlerV1 := NewHttpHandler(NewContextHandler(false))(func(w http.ResponseWriter, r *http.Request) HttpHandleResult {
+		_, err := GetSort(r, "sort", testSortAllowlist)
+		return HttpHandleResult{Error: err}
+	})
+
+	w = httptest.NewRecorder()
+	handlerV1.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?sort=password", nil))
+	assert.Equal(t, ErrInvalidSort.HttpStatus, w.Code, "Expect V1 write the wrapped error status")
+	assert.Contains(t, w.Body.String(), ErrInvalidSort.ResponseDesc, "Expect V1 registered message")
+}
@@ -5,11 +5,20 @@ import (
 )
 
 type Pagination struct {
-	PageSize    int `json:"page_size" mapstructure:"page_size"`
-	CurrentPage int `json:"current_page" mapstructure:"current_page"`
-	TotalPage   int `json:"total_page" mapstructure:"total_page"`
-	NextPage    int `json:"next_page" mapstructure:"next_page"`
-	TotalData   int `json:"total_data" mapstructure:"total_data"`
+	PageSize    int  `json:"page_size" mapstructure:"page_size"`
+	CurrentPage int  `json:"current_page" mapstructure:"current_page"`
+	TotalPage   int  `json:"total_page" mapstructure:"total_page"`
+	NextPage    int  `json:"next_page" mapstructure:"next_page"`
+	TotalData   int  `json:"total_data" mapstructure:"total_data"`
+	PrevPage    int  `json:"prev_page" mapstructure:"prev_page"`
+	HasNext     bool `json:"has_next" mapstructure:"has_next"`
+	HasPrev     bool `json:"has_prev" mapstructure:"has_prev"`
+	// NextCursor and PrevCursor are only set on keyset pagination, see GetCursorPagination
+	NextCursor string `json:"next_cursor,omitempty" mapstructure:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty" mapstructure:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more" mapstructure:"has_more"`
+	// Links is only set when HandlerContext.PaginationLinks is enabled
+	Links *PaginationLinks `json:"links,omitempty" mapstructure:"links,omitempty"`
 }
 
 type PaginationFields struct {
@@ -17,6 +26,8 @@ type PaginationFields struct {
 	SortField   string
 	LimitField  string
 	OffsetField string
+	AfterField  string
+	BeforeField string
 }
 
 type RequestPagination struct {
@@ -24,6 +35,9 @@ type RequestPagination struct {
 	Sort   []string
 	Limit  int
 	Offset int
+	// After and Before are keyset pagination cursors, decode them using CursorCodec
+	After  string
+	Before string
 }
 
 type HttpHandleResult struct {
@@ -32,6 +46,13 @@ type HttpHandleResult struct {
 	Pagination      *Pagination
 	Error           error
 	IsPlainResponse bool
+	// Headers and Cookies are applied before the body is written, use SetHeader and AddCookie.
+	// Cookies are sent with HttpOnly and Secure, InsecureCookies (AddInsecureCookie) are sent as set
+	Headers         http.Header
+	Cookies         []*http.Cookie
+	InsecureCookies []*http.Cookie
+	// RedirectURL redirect the client instead of writing the envelope, use SetRedirect
+	RedirectURL string
 }
 
 type HttpHandleResultV2 struct {
@@ -41,6 +62,13 @@ type HttpHandleResultV2 struct {
 	Error           error
 	Message         []string
 	IsPlainResponse bool
+	// Headers and Cookies are applied before the body is written, use SetHeader and AddCookie.
+	// Cookies are sent with HttpOnly and Secure, InsecureCookies (AddInsecureCookie) are sent as set
+	Headers         http.Header
+	Cookies         []*http.Cookie
+	InsecureCookies []*http.Cookie
+	// RedirectURL redirect the client instead of writing the envelope, use SetRedirect
+	RedirectURL string
 }
 
 type Response struct {
@@ -121,3 +149,124 @@ var ErrRequestEntityTooLarge = &ErrorResponse{
 	},
 	HttpStatus: http.StatusRequestEntityTooLarge,
 }
+
+var ErrOriginNotAllowed = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Origin not allowed",
+	},
+	HttpStatus: http.StatusForbidden,
+}
+
+var ErrCORSRequestNotAllowed = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Method or header not allowed by CORS policy",
+	},
+	HttpStatus: http.StatusForbidden,
+}
+
+var ErrTooManyRequests = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Too many requests",
+	},
+	HttpStatus: http.StatusTooManyRequests,
+}
+
+// ErrGatewayTimeout is returned when handler does not finish before its timeout,
+// using 503 like net/http TimeoutHandler
+var ErrGatewayTimeout = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Request timeout",
+	},
+	HttpStatus: http.StatusServiceUnavailable,
+}
+
+var ErrIdempotencyKeyRequired = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Idempotency key is required",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrIdempotencyKeyInFlight = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Request with the same idempotency key is still in progress",
+	},
+	HttpStatus: http.StatusConflict,
+}
+
+var ErrRouteNotFound = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Route not found",
+	},
+	HttpStatus: http.StatusNotFound,
+}
+
+var ErrMethodNotAllowed = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Method not allowed",
+	},
+	HttpStatus: http.StatusMethodNotAllowed,
+}
+
+var ErrInvalidPathParam = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid path parameter",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrServerNotReady = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Server is not ready",
+	},
+	HttpStatus: http.StatusServiceUnavailable,
+}
+
+var ErrServiceUnhealthy = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Service unhealthy",
+	},
+	HttpStatus: http.StatusServiceUnavailable,
+}
+
+var ErrUnsupportedVersion = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Unsupported API version",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrInvalidCursor = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid pagination cursor",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrInvalidSort = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid sort field",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrInvalidFilter = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid filter",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrInvalidPage = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid page",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
+
+var ErrInvalidPageSize = &ErrorResponse{
+	Response: Response{
+		ResponseDesc: "Invalid page size",
+	},
+	HttpStatus: http.StatusBadRequest,
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutHandlerV2(t *testing.T) {
+	req, err := http.NewRequest("GET", "/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx, WithTimeout(10*time.Millisecond))
+
+	finished := make(chan error)
+	testHandler := newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		<-r.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("X-Late", "true")
+		_, err := w.Write([]byte("late"))
+		finished <- err
+
+		response.Data = "OK"
+		return
+	})
+
+	testHandler.ServeHTTP(w, req)
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(body, respJson)
+
+	assert.Equal(t, http.StatusServiceUnavailable, respJson.StatusCode, "Expect 503 status code in body")
+	assert.Equal(t, false, respJson.Success, "Expect Success False")
+	assert.Equal(t, []string{ErrGatewayTimeout.ResponseDesc}, respJson.Message, "Expect timeout message")
+
+	assert.Equal(t, http.ErrHandlerTimeout, <-finished, "Expect late write rejected")
+	assert.Equal(t, "", w.Header().Get("X-Late"), "Expect late header not written")
+}
+
+func TestTimeoutHandlerV2Success(t *testing.T) {
+	req, err := http.NewRequest("GET", "/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx, WithTimeout(time.Second))
+
+	testHandler := newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		_, hasDeadline := r.Context().Deadline()
+		assert.Equal(t, true, hasDeadline, "Expect deadline context")
+
+		w.Header().Set("X-Custom", "value")
+		response.Data = "OK"
+		response.StatusCode = http.StatusCreated
+		return
+	})
+
+	testHandler.ServeHTTP(w, req)
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	respJson := &ResponseV2{}
+	_ = json.Unmarshal(body, respJson)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Expect 200 status code")
+	assert.Equal(t, http.StatusCreated, respJson.StatusCode, "Expect 201 status code in body")
+	assert.Equal(t, "OK", respJson.Data, "Expect OK data")
+	assert.Equal(t, "value", resp.Header.Get("X-Custom"), "Expect handler header")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "Expect json content type")
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agung-project/golib/trace"
+	"github.com/go-resty/resty/v2"
+)
+
+const traceParentHeader = "traceparent"
+
+// startServerSpan start span for incoming request, continuing the trace from traceparent header if exist
+func startServerSpan(r *http.Request) (*http.Request, trace.Span) {
+	ctx := r.Context()
+	if sc, ok := trace.ParseTraceParent(r.Header.Get(traceParentHeader)); ok {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+	}
+
+	name := r.Method
+	route := RoutePattern(r)
+	if route != "" {
+		name += " " + route
+	}
+
+	ctx, span := trace.Start(ctx, name)
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.target", r.URL.Path)
+	if route != "" {
+		span.SetAttribute("http.route", route)
+	}
+
+	return r.WithContext(ctx), span
+}
+
+type clientSpanKey struct{}
+
+// instrumentTracing start span for outgoing request and inject traceparent header,
+// the span is child of the span in request context (set using SetContext)
+func instrumentTracing(c *resty.Client) {
+	c.OnBeforeRequest(func(client *resty.Client, req *resty.Request) error {
+		span, ok := req.Context().Value(clientSpanKey{}).(trace.Span)
+		if !ok {
+			// user defined hook run before resty apply the path params, so the name use the url template
+			var ctx context.Context
+			ctx, span = trace.Start(req.Context(), "HTTP "+req.Method+" "+req.URL)
+			span.SetAttribute("http.method", req.Method)
+			span.SetAttribute("http.url", req.URL)
+			req.SetContext(context.WithValue(ctx, clientSpanKey{}, span))
+		}
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			req.SetHeader(traceParentHeader, sc.TraceParent())
+		}
+		return nil
+	})
+
+	c.OnAfterResponse(func(client *resty.Client, res *resty.Response) error {
+		if span, ok := res.Request.Context().Value(clientSpanKey{}).(trace.Span); ok {
+			span.SetAttribute("http.status_code", res.StatusCode())
+			span.End()
+		}
+		return nil
+	})
+
+	c.OnError(func(req *resty.Request, err error) {
+		if span, ok := req.Context().Value(clientSpanKey{}).(trace.Span); ok {
+			span.SetError(err)
+			span.End()
+		}
+	})
+}
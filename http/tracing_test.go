@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agung-project/golib/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracingHandlerAndRestClient(t *testing.T) {
+	exporter := trace.NewInMemoryExporter()
+	trace.SetTracer(trace.NewTracer(exporter))
+	defer trace.SetTracer(nil)
+
+	var downstreamTraceParent string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamTraceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer downstream.Close()
+
+	client := NewRestClient(downstream.URL)
+
+	handlerCtx := NewContextHandlerV2(false)
+	newHandler := NewHttpHandlerV2(handlerCtx)
+	router := NewRouter(handlerCtx)
+	router.Get("/users/{id}", newHandler(func(w http.ResponseWriter, r *http.Request) (response HttpHandleResultV2) {
+		_, err := client.HttpClient.R().SetContext(r.Context()).SetPathParam("id", PathParam(r, "id")).Get("/profiles/{id}")
+		response.Error = err
+		return
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.Spans()
+	assert.Equal(t, 2, len(spans), "Expect client and server span")
+
+	clientSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "HTTP GET /profiles/{id}", clientSpan.Name, "Expect client span name")
+	assert.Equal(t, "GET /users/{id}", serverSpan.Name, "Expect server span name with route")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.SpanContext.TraceID.String(), "Expect incoming trace continued")
+	assert.Equal(t, serverSpan.SpanContext.SpanID, clientSpan.ParentSpanID, "Expect client span is child of server span")
+	assert.Equal(t, clientSpan.SpanContext.TraceParent(), downstreamTraceParent, "Expect traceparent injected")
+	assert.Equal(t, http.StatusOK, clientSpan.Attributes["http.status_code"], "Expect client status code")
+	assert.Equal(t, http.StatusOK, serverSpan.Attributes["http.status_code"], "Expect server status code")
+}
@@ -0,0 +1,264 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	EnvelopeV1 = "1"
+	EnvelopeV2 = "2"
+)
+
+// EnvelopeRenderer write the handler result as specific envelope version and return the written http status
+type EnvelopeRenderer func(c HandlerContextV2, w http.ResponseWriter, result HttpHandleResultV2) int
+
+// VersionResolver return the envelope version requested by client, empty string means not specified
+type VersionResolver func(r *http.Request) string
+
+type VersionedOption func(*VersionedHandler)
+
+// VersionedHandler render one handler result as V1 (SuccessResponse) or V2 (ResponseV2) envelope
+// based on the version requested by client, so the handler is not duplicated during client migration
+type VersionedHandler struct {
+	// H is handler, with return interface{} as data object, error for error type
+	H              func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2
+	C              HandlerContextV2
+	IsDebug        bool
+	Resolver       VersionResolver
+	DefaultVersion string
+	Renderers      map[string]EnvelopeRenderer
+}
+
+// WithVersionResolver set how the version is resolved, default is
+// VersionResolvers(VersionFromHeader("X-API-Version"), VersionFromAccept("version"), VersionFromPathPrefix())
+func WithVersionResolver(resolver VersionResolver) VersionedOption {
+	return func(h *VersionedHandler) {
+		h.Resolver = resolver
+	}
+}
+
+// WithDefaultVersion set version used when client does not specify it, default is EnvelopeV1 for old clients
+func WithDefaultVersion(version string) VersionedOption {
+	return func(h *VersionedHandler) {
+		h.DefaultVersion = normalizeVersion(version)
+	}
+}
+
+// WithEnvelopeRenderer register renderer of new envelope version or replace the built in one
+func WithEnvelopeRenderer(version string, renderer EnvelopeRenderer) VersionedOption {
+	return func(h *VersionedHandler) {
+		h.Renderers[normalizeVersion(version)] = renderer
+	}
+}
+
+// NewVersionedHandler create versioned handler factory, it panics when DefaultVersion has no renderer
+func NewVersionedHandler(c HandlerContextV2, opts ...VersionedOption) func(handler func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2) VersionedHandler {
+	base := VersionedHandler{
+		C:              c,
+		IsDebug:        c.IsDebug,
+		Resolver:       VersionResolvers(VersionFromHeader("X-API-Version"), VersionFromAccept("version"), VersionFromPathPrefix()),
+		DefaultVersion: EnvelopeV1,
+		Renderers: map[string]EnvelopeRenderer{
+			EnvelopeV1: RenderEnvelopeV1,
+			EnvelopeV2: RenderEnvelopeV2,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&base)
+	}
+
+	if base.Renderers[base.DefaultVersion] == nil {
+		panic(fmt.Sprintf("versioned handler: no renderer for default version %q", base.DefaultVersion))
+	}
+
+	return func(handler func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2) VersionedHandler {
+		h := base
+		h.H = handler
+		return h
+	}
+}
+
+type envelopeVersionKey struct{}
+
+// EnvelopeVersion return the envelope version resolved by VersionedHandler
+func EnvelopeVersion(r *http.Request) string {
+	version, _ := r.Context().Value(envelopeVersionKey{}).(string)
+	return version
+}
+
+func (h VersionedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r, span := startServerSpan(r)
+	defer span.End()
+
+	version := normalizeVersion(h.Resolver(r))
+	if version == "" {
+		version = h.DefaultVersion
+	}
+
+	render, ok := h.Renderers[version]
+	if !ok {
+		render = h.Renderers[h.DefaultVersion]
+		setRequestCode(r, ErrUnsupportedVersion.HttpStatus)
+		span.SetError(ErrUnsupportedVersion)
+		span.SetAttribute("http.status_code", render(h.C, w, HttpHandleResultV2{Error: ErrUnsupportedVersion}))
+		return
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), envelopeVersionKey{}, version))
+	span.SetAttribute("http.envelope_version", version)
+
+	result := h.H(w, r)
+
+	if h.IsDebug {
+		logDebugRequest(r)
+	}
+
+	writeResultHeaders(w, result.Headers, result.Cookies, result.InsecureCookies)
+
+	if result.Error == nil && result.RedirectURL != "" {
+		code := redirectStatus(result.StatusCode)
+		setRequestCode(r, code)
+		span.SetAttribute("http.status_code", code)
+		http.Redirect(w, r, result.RedirectURL, code)
+		return
+	}
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Msgf("Response: %+v", result.Data)
+		writer := CustomWriterV2{C: h.C}
+		errorResponse, _ := writer.errorResponse(result.Error)
+		setRequestCode(r, errorResponse.HttpStatus)
+		span.SetError(result.Error)
+	} else {
+		if result.StatusCode == 0 {
+			setRequestCode(r, http.StatusOK)
+		} else {
+			setRequestCode(r, result.StatusCode)
+		}
+
+		if !result.IsPlainResponse {
+			result.Pagination = writePaginationLinks(w, r, result.Pagination, h.C.PaginationLinks)
+		}
+	}
+
+	span.SetAttribute("http.status_code", render(h.C, w, result))
+}
+
+// RenderEnvelopeV1 write result as SuccessResponse/ErrorResponse, result message is not part of V1 envelope
+func RenderEnvelopeV1(c HandlerContextV2, w http.ResponseWriter, result HttpHandleResultV2) int {
+	writer := CustomWriter{C: HandlerContext{E: c.E, IsDebug: c.IsDebug, Logger: c.Logger}}
+
+	if result.Error != nil {
+		errorResponse := writer.errorResponse(result.Error)
+		writeErrorResponse(w, errorResponse)
+		return errorResponse.HttpStatus
+	}
+
+	code := result.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	if result.IsPlainResponse {
+		writer.WritePlain(w, result.Data, result.StatusCode)
+	} else {
+		writer.Write(w, result.Data, result.StatusCode, result.Pagination)
+	}
+
+	return code
+}
+
+// RenderEnvelopeV2 write result as ResponseV2
+func RenderEnvelopeV2(c HandlerContextV2, w http.ResponseWriter, result HttpHandleResultV2) int {
+	writer := CustomWriterV2{C: c}
+
+	if result.Error != nil {
+		_, statusCode := writer.errorResponse(result.Error)
+		writer.WriteError(w, result.Error, result.Message)
+		return statusCode
+	}
+
+	if result.IsPlainResponse {
+		writer.WritePlain(w, result.Data, result.StatusCode)
+		if result.StatusCode == 0 {
+			return http.StatusOK
+		}
+		return result.StatusCode
+	}
+
+	writer.Write(w, result.Data, result.StatusCode, result.Pagination, result.Message)
+	return http.StatusOK
+}
+
+// VersionResolvers return the first version resolved by resolvers
+func VersionResolvers(resolvers ...VersionResolver) VersionResolver {
+	return func(r *http.Request) string {
+		for _, resolver := range resolvers {
+			if version := resolver(r); version != "" {
+				return version
+			}
+		}
+
+		return ""
+	}
+}
+
+// VersionFromHeader read version from header, e.g. X-API-Version: 2
+func VersionFromHeader(name string) VersionResolver {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// VersionFromAccept read version from Accept media type parameter, e.g. Accept: application/json; version=2
+func VersionFromAccept(param string) VersionResolver {
+	return func(r *http.Request) string {
+		for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+			_, params, err := mime.ParseMediaType(accept)
+			if err != nil {
+				continue
+			}
+
+			if version := params[param]; version != "" {
+				return version
+			}
+		}
+
+		return ""
+	}
+}
+
+// VersionFromPathPrefix read version from the first path segment, e.g. /v2/users
+func VersionFromPathPrefix() VersionResolver {
+	return func(r *http.Request) string {
+		segment := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[0]
+		if len(segment) < 2 || (segment[0] != 'v' && segment[0] != 'V') {
+			return ""
+		}
+
+		for _, c := range segment[1:] {
+			if c < '0' || c > '9' {
+				return ""
+			}
+		}
+
+		return segment
+	}
+}
+
+// normalizeVersion make "v2", "V2" and "2" the same version
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		return version[1:]
+	}
+
+	return version
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVersionedTestHandler(opts ...VersionedOption) VersionedHandler {
+	c := NewContextHandlerV2(false)
+	return NewVersionedHandler(c, opts...)(func(w http.ResponseWriter, r *http.Request) HttpHandleResultV2 {
+		if r.URL.Query().Get("fail") != "" {
+			return HttpHandleResultV2{Error: ErrUnauthorized}
+		}
+
+		return HttpHandleResultV2{
+			Data:       []string{"OK", EnvelopeVersion(r)},
+			Pagination: &Pagination{PageSize: 10, CurrentPage: 1, TotalPage: 1, TotalData: 2},
+			Message:    []string{"success"},
+		}
+	})
+}
+
+func TestVersionedHandlerResolveVersion(t *testing.T) {
+	handler := newVersionedTestHandler()
+
+	tests := []struct {
+		name    string
+		target  string
+		header  string
+		value   string
+		version string
+	}{
+		{name: "default", target: "/users", version: EnvelopeV1},
+		{name: "header", target: "/users", header: "X-API-Version", value: "2", version: EnvelopeV2},
+		{name: "header with prefix", target: "/users", header: "X-API-Version", value: "v2", version: EnvelopeV2},
+		{name: "accept", target: "/users", header: "Accept", value: "text/html, application/json; version=2", version: EnvelopeV2},
+		{name: "path prefix", target: "/v2/users", version: EnvelopeV2},
+		{name: "path prefix v1", target: "/v1/users", version: EnvelopeV1},
+		{name: "header before path", target: "/v2/users", header: "X-API-Version", value: "1", version: EnvelopeV1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			body := map[string]interface{}{}
+			assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body), "Expect JSON body")
+			assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+
+			if tt.version == EnvelopeV2 {
+				assert.Equal(t, true, body["success"], "Expect V2 envelope")
+				assert.Equal(t, []interface{}{"OK", "2"}, body["data"].(map[string]interface{})["data"], "Expect data in pagination")
+			} else {
+				assert.NotContains(t, body, "success", "Expect V1 envelope")
+				assert.Equal(t, []interface{}{"OK", "1"}, body["data"], "Expect data")
+				assert.Equal(t, float64(10), body["page_size"], "Expect pagination")
+			}
+		})
+	}
+}
+
+func TestVersionedHandlerError(t *testing.T) {
+	handler := newVersionedTestHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?fail=1", nil))
+
+	respV1 := ErrorResponse{}
+	json.Unmarshal(w.Body.Bytes(), &respV1)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "Expect V1 error http status")
+	assert.Equal(t, ErrUnauthorized.ResponseDesc, respV1.ResponseDesc, "Expect V1 error message")
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users?fail=1", nil)
+	req.Header.Set("X-API-Version", "2")
+	handler.ServeHTTP(w, req)
+
+	respV2 := ResponseV2{}
+	json.Unmarshal(w.Body.Bytes(), &respV2)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expect V2 error http status")
+	assert.Equal(t, http.StatusUnauthorized, respV2.StatusCode, "Expect V2 error status in body")
+	assert.Equal(t, []string{ErrUnauthorized.ResponseDesc}, respV2.Message, "Expect V2 error message")
+}
+
+func TestVersionedHandlerUnsupportedVersion(t *testing.T) {
+	handler := newVersionedTestHandler(WithDefaultVersion("v2"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("X-API-Version", "9")
+	handler.ServeHTTP(w, req)
+
+	resp := ResponseV2{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expect 400 status code")
+	assert.Equal(t, []string{ErrUnsupportedVersion.ResponseDesc}, resp.Message, "Expect unsupported version error in default envelope")
+}
+
+func TestVersionedHandlerCustomRenderer(t *testing.T) {
+	renderV3 := func(c HandlerContextV2, w http.ResponseWriter, result HttpHandleResultV2) int {
+		status := http.StatusOK
+		if result.Error != nil {
+			status = http.StatusInternalServerError
+		}
+		writeResponse(w, map[string]interface{}{"result": result.Data, "error": result.Error != nil}, "application/json", status)
+		return status
+	}
+
+	handler := newVersionedTestHandler(
+		WithVersionResolver(VersionFromHeader("X-Envelope")),
+		WithEnvelopeRenderer("3", renderV3),
+	)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v2/users", nil)
+	req.Header.Set("X-Envelope", "v3")
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expect 200 status code")
+	assert.JSONEq(t, `{"result":["OK","3"],"error":false}`, w.Body.String(), "Expect custom envelope")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/users", nil))
+	assert.NotContains(t, w.Body.String(), "success", "Expect path prefix is ignored by custom resolver")
+}
+
+func TestVersionedHandlerDefaultVersionWithoutRenderer(t *testing.T) {
+	assert.Panics(t, func() {
+		NewVersionedHandler(NewContextHandlerV2(false), WithDefaultVersion("3"))
+	}, "Expect default version without renderer rejected at construction")
+
+	assert.NotPanics(t, func() {
+		NewVersionedHandler(NewContextHandlerV2(false), WithDefaultVersion("3"), WithEnvelopeRenderer("3", RenderEnvelopeV2))
+	}, "Expect default version with renderer accepted")
+}
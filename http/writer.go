@@ -13,6 +13,8 @@ type HandlerContext struct {
 	E       map[error]*ErrorResponse
 	IsDebug bool
 	Logger  zerolog.Logger
+	// PaginationLinks write first, prev, next and last links of paginated response when enabled
+	PaginationLinks PaginationLinksConfig
 }
 
 func NewContextHandler(isDebug bool) HandlerContext {
@@ -22,6 +24,23 @@ func NewContextHandler(isDebug bool) HandlerContext {
 		ErrUnauthorized:           ErrUnauthorized,
 		ErrInvalidHeaderSignature: ErrInvalidHeaderSignature,
 		ErrInvalidHeaderTime:      ErrInvalidHeaderTime,
+		ErrOriginNotAllowed:       ErrOriginNotAllowed,
+		ErrCORSRequestNotAllowed:  ErrCORSRequestNotAllowed,
+		ErrTooManyRequests:        ErrTooManyRequests,
+		ErrGatewayTimeout:         ErrGatewayTimeout,
+		ErrIdempotencyKeyRequired: ErrIdempotencyKeyRequired,
+		ErrIdempotencyKeyInFlight: ErrIdempotencyKeyInFlight,
+		ErrRouteNotFound:          ErrRouteNotFound,
+		ErrMethodNotAllowed:       ErrMethodNotAllowed,
+		ErrInvalidPathParam:       ErrInvalidPathParam,
+		ErrServerNotReady:         ErrServerNotReady,
+		ErrServiceUnhealthy:       ErrServiceUnhealthy,
+		ErrUnsupportedVersion:     ErrUnsupportedVersion,
+		ErrInvalidCursor:          ErrInvalidCursor,
+		ErrInvalidSort:            ErrInvalidSort,
+		ErrInvalidFilter:          ErrInvalidFilter,
+		ErrInvalidPage:            ErrInvalidPage,
+		ErrInvalidPageSize:        ErrInvalidPageSize,
 	}
 
 	return HandlerContext{
@@ -82,22 +101,26 @@ func (c *CustomWriter) WritePlain(w http.ResponseWriter, data interface{}, statu
 
 // WriteError sending error response based on err type
 func (c *CustomWriter) WriteError(w http.ResponseWriter, err error) {
+	writeErrorResponse(w, c.errorResponse(err))
+}
+
+// errorResponse return the registered error response for err
+func (c *CustomWriter) errorResponse(err error) *ErrorResponse {
 	if len(c.C.E) > 0 {
-		errorResponse := LookupError(c.C.E, err)
+		errorResponse := lookupWrappedError(c.C.E, err)
 		if errorResponse == nil {
 			errorResponse = ErrUnknown
 		}
 
-		writeErrorResponse(w, errorResponse)
-	} else {
-		var errorResponse = &ErrorResponse{}
-		if errors.As(err, &errorResponse) {
-			writeErrorResponse(w, errorResponse)
-		} else {
-			errorResponse = ErrUnknown
-			writeErrorResponse(w, errorResponse)
-		}
+		return errorResponse
+	}
+
+	var errorResponse = &ErrorResponse{}
+	if !errors.As(err, &errorResponse) {
+		errorResponse = ErrUnknown
 	}
+
+	return errorResponse
 }
 
 func writeResponse(w http.ResponseWriter, response interface{}, contentType string, httpStatus int) {
@@ -130,3 +153,23 @@ func LookupError(lookup map[error]*ErrorResponse, err error) (res *ErrorResponse
 
 	return
 }
+
+// lookupWrappedError lookup err, then the errors it wraps (e.g. fmt.Errorf("%w, ...", ErrInvalidSort)),
+// the nearest registered error is returned. Wrapped error of uncomparable type is skipped because it can not be map key
+func lookupWrappedError(lookup map[error]*ErrorResponse, err error) *ErrorResponse {
+	if errorResponse := LookupError(lookup, err); errorResponse != nil {
+		return errorResponse
+	}
+
+	for err = errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		if !reflect.TypeOf(err).Comparable() {
+			continue
+		}
+
+		if errorResponse := LookupError(lookup, err); errorResponse != nil {
+			return errorResponse
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,8 @@ type HandlerContextV2 struct {
 	E       map[error]*ErrorResponse
 	IsDebug bool
 	Logger  zerolog.Logger
+	// PaginationLinks write first, prev, next and last links of paginated response when enabled
+	PaginationLinks PaginationLinksConfig
 }
 
 func NewContextHandlerV2(isDebug bool) HandlerContextV2 {
@@ -20,6 +22,23 @@ func NewContextHandlerV2(isDebug bool) HandlerContextV2 {
 		ErrUnauthorized:           ErrUnauthorized,
 		ErrInvalidHeaderSignature: ErrInvalidHeaderSignature,
 		ErrInvalidHeaderTime:      ErrInvalidHeaderTime,
+		ErrOriginNotAllowed:       ErrOriginNotAllowed,
+		ErrCORSRequestNotAllowed:  ErrCORSRequestNotAllowed,
+		ErrTooManyRequests:        ErrTooManyRequests,
+		ErrGatewayTimeout:         ErrGatewayTimeout,
+		ErrIdempotencyKeyRequired: ErrIdempotencyKeyRequired,
+		ErrIdempotencyKeyInFlight: ErrIdempotencyKeyInFlight,
+		ErrRouteNotFound:          ErrRouteNotFound,
+		ErrMethodNotAllowed:       ErrMethodNotAllowed,
+		ErrInvalidPathParam:       ErrInvalidPathParam,
+		ErrServerNotReady:         ErrServerNotReady,
+		ErrServiceUnhealthy:       ErrServiceUnhealthy,
+		ErrUnsupportedVersion:     ErrUnsupportedVersion,
+		ErrInvalidCursor:          ErrInvalidCursor,
+		ErrInvalidSort:            ErrInvalidSort,
+		ErrInvalidFilter:          ErrInvalidFilter,
+		ErrInvalidPage:            ErrInvalidPage,
+		ErrInvalidPageSize:        ErrInvalidPageSize,
 	}
 
 	return HandlerContextV2{
@@ -89,12 +108,41 @@ func (c *CustomWriterV2) WriteError(w http.ResponseWriter, err error, msg []stri
 	resp.Message = msg
 	resp.Data = []interface{}{}
 
+	errorResponse, statusCode := c.errorResponse(err)
+
+	if len(msg) <= 0 {
+		resp.Message = append(resp.Message, errorResponse.ResponseDesc)
+	}
+	resp.StatusCode = errorResponse.HttpStatus
+
+	writeResponseV2(w, resp, statusCode)
+
+}
+
+// writeErrorWithStatus write error response of err with the given http status instead of the default error status,
+// it is used where client, proxy or probe act on the real http status (e.g. 429 and 503). Nil data is written as empty array
+func (c *CustomWriterV2) writeErrorWithStatus(w http.ResponseWriter, err error, data interface{}, httpStatus int) {
+	if data == nil {
+		data = []interface{}{}
+	}
+
+	errorResponse, _ := c.errorResponse(err)
+	writeResponseV2(w, ResponseV2{
+		StatusCode: errorResponse.HttpStatus,
+		Message:    []string{errorResponse.ResponseDesc},
+		Success:    false,
+		Data:       data,
+	}, httpStatus)
+}
+
+// errorResponse return the registered error response and the http status code for err
+func (c *CustomWriterV2) errorResponse(err error) (*ErrorResponse, int) {
 	statusCode := http.StatusBadRequest
 
 	var errorResponse = &ErrorResponse{}
 
 	if len(c.C.E) > 0 {
-		errorResponse = LookupError(c.C.E, err)
+		errorResponse = lookupWrappedError(c.C.E, err)
 		if errorResponse == nil {
 			errorResponse = ErrUnknown
 			statusCode = http.StatusInternalServerError
@@ -106,13 +154,7 @@ func (c *CustomWriterV2) WriteError(w http.ResponseWriter, err error, msg []stri
 		}
 	}
 
-	if len(msg) <= 0 {
-		resp.Message = append(resp.Message, errorResponse.ResponseDesc)
-	}
-	resp.StatusCode = errorResponse.HttpStatus
-
-	writeResponseV2(w, resp, statusCode)
-
+	return errorResponse, statusCode
 }
 
 func writeResponseV2(w http.ResponseWriter, response ResponseV2, statusCode int) {
@@ -2,8 +2,11 @@ package oss
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 
+	"github.com/agung-project/golib/trace"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/rs/zerolog/log"
 )
@@ -21,6 +24,11 @@ type OSSInterface interface {
 	Upload(key string, object []byte) (url string, err error)
 }
 
+// Pinger is implemented by client that can check the bucket is reachable, the client of NewClient implement it
+type Pinger interface {
+	Ping() error
+}
+
 type ossInstance struct {
 	*OSSCOnfig
 	client *oss.Client
@@ -73,3 +81,59 @@ func (o *ossInstance) Upload(key string, object []byte) (url string, err error)
 	url = fmt.Sprintf(o.Url+"/%s", key)
 	return
 }
+
+// Ping check the bucket is reachable, client must be started
+func (o *ossInstance) Ping() error {
+	if o.client == nil {
+		return errors.New("oss client is not started")
+	}
+
+	exist, err := o.client.IsBucketExist(o.Bucket)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return fmt.Errorf("bucket %s does not exist", o.Bucket)
+	}
+
+	return nil
+}
+
+// TracedOSS wrap OSSInterface to create span for each upload
+type TracedOSS struct {
+	OSSInterface
+}
+
+func WithTracing(o OSSInterface) *TracedOSS {
+	return &TracedOSS{o}
+}
+
+// Ping ping the wrapped client, it return error when the wrapped client does not implement Pinger
+func (t *TracedOSS) Ping() error {
+	pinger, ok := t.OSSInterface.(Pinger)
+	if !ok {
+		return errors.New("oss client does not implement Ping")
+	}
+
+	return pinger.Ping()
+}
+
+func (t *TracedOSS) Upload(key string, object []byte) (url string, err error) {
+	return t.UploadContext(context.Background(), key, object)
+}
+
+// UploadContext upload the object with span as child of the span in ctx
+func (t *TracedOSS) UploadContext(ctx context.Context, key string, object []byte) (url string, err error) {
+	_, span := trace.Start(ctx, "OSS Upload")
+	defer span.End()
+
+	span.SetAttribute("oss.key", key)
+	span.SetAttribute("oss.size", len(object))
+
+	url, err = t.OSSInterface.Upload(key, object)
+	if err != nil {
+		span.SetError(err)
+	}
+	return
+}
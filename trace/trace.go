@@ -0,0 +1,289 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+type SpanID [8]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext is the identity of span that is propagated between services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// TraceParent format span context as W3C traceparent header value
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceParent parse W3C traceparent header value, ok is false when the value is invalid
+func ParseTraceParent(value string) (sc SpanContext, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, false
+	}
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, false
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, false
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+
+	return sc, sc.IsValid()
+}
+
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key string, value interface{})
+	SetError(err error)
+	End()
+}
+
+type Tracer interface {
+	// Start create child span of the span in ctx (or remote span context), and return ctx that contains the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// SpanData is the ended span passed to exporter
+type SpanData struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Attributes   map[string]interface{}
+	Err          error
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+type Exporter interface {
+	ExportSpan(span SpanData)
+}
+
+type spanKey struct{}
+
+type remoteSpanKey struct{}
+
+var (
+	tracerMu     sync.RWMutex
+	globalTracer Tracer = noopTracer{}
+)
+
+// SetTracer set the global tracer used by golib packages, default is no-op tracer
+func SetTracer(t Tracer) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+
+	if t == nil {
+		t = noopTracer{}
+	}
+	globalTracer = t
+}
+
+func GetTracer() Tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+
+	return globalTracer
+}
+
+// Start create span using the global tracer
+func Start(ctx context.Context, name string) (context.Context, Span) {
+	return GetTracer().Start(ctx, name)
+}
+
+// SpanFromContext return the current span, no-op span if there is no span in ctx
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+
+	return noopSpan{sc: remoteSpanContext(ctx)}
+}
+
+// ContextWithRemoteSpanContext set span context extracted from incoming request as the parent of the next span
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanKey{}, sc)
+}
+
+func remoteSpanContext(ctx context.Context) SpanContext {
+	if sc, ok := ctx.Value(remoteSpanKey{}).(SpanContext); ok {
+		return sc
+	}
+
+	return SpanContext{}
+}
+
+func parentSpanContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span.SpanContext()
+	}
+
+	return remoteSpanContext(ctx)
+}
+
+// noopTracer does not record anything, but keep the incoming span context so it is still propagated
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := noopSpan{sc: parentSpanContext(ctx)}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type noopSpan struct {
+	sc SpanContext
+}
+
+func (s noopSpan) SpanContext() SpanContext { return s.sc }
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+
+func (noopSpan) SetError(err error) {}
+
+func (noopSpan) End() {}
+
+type tracer struct {
+	exporter Exporter
+}
+
+// NewTracer create tracer that send ended span to the exporter
+func NewTracer(exporter Exporter) Tracer {
+	return &tracer{exporter: exporter}
+}
+
+func (t *tracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent := parentSpanContext(ctx)
+
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			Attributes: map[string]interface{}{},
+			StartTime:  time.Now(),
+		},
+	}
+
+	if parent.IsValid() {
+		s.data.SpanContext.TraceID = parent.TraceID
+		s.data.SpanContext.Sampled = parent.Sampled
+		s.data.ParentSpanID = parent.SpanID
+	} else {
+		rand.Read(s.data.SpanContext.TraceID[:])
+		s.data.SpanContext.Sampled = true
+	}
+	rand.Read(s.data.SpanContext.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+type span struct {
+	mu     sync.Mutex
+	tracer *tracer
+	data   SpanData
+	ended  bool
+}
+
+func (s *span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Attributes[key] = value
+}
+
+func (s *span) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Err = err
+}
+
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+
+	data := s.data
+	data.Attributes = make(map[string]interface{}, len(s.data.Attributes))
+	for k, v := range s.data.Attributes {
+		data.Attributes[k] = v
+	}
+	s.mu.Unlock()
+
+	if s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(data)
+	}
+}
+
+// InMemoryExporter keep ended spans in memory, use it in tests
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Spans return the ended spans in end order
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData{}, e.spans...)
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
@@ -0,0 +1,64 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceParent(value)
+	assert.Equal(t, true, ok, "Expect valid traceparent")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String(), "Expect trace id")
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String(), "Expect span id")
+	assert.Equal(t, true, sc.Sampled, "Expect sampled")
+	assert.Equal(t, value, sc.TraceParent(), "Expect same traceparent")
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+	} {
+		_, ok = ParseTraceParent(invalid)
+		assert.Equal(t, false, ok, "Expect invalid traceparent %q", invalid)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	remote, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextWithRemoteSpanContext(context.Background(), remote)
+
+	ctx, parent := tracer.Start(ctx, "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttribute("key", "value")
+	child.SetError(errors.New("failed"))
+	child.End()
+	parent.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	assert.Equal(t, 2, len(spans), "Expect 2 spans exported once")
+	assert.Equal(t, "child", spans[0].Name, "Expect child ended first")
+	assert.Equal(t, remote.TraceID, spans[0].SpanContext.TraceID, "Expect child in remote trace")
+	assert.Equal(t, spans[1].SpanContext.SpanID, spans[0].ParentSpanID, "Expect child parent is parent span")
+	assert.Equal(t, remote.SpanID, spans[1].ParentSpanID, "Expect parent of parent is remote span")
+	assert.Equal(t, "value", spans[0].Attributes["key"], "Expect attribute")
+	assert.EqualError(t, spans[0].Err, "failed", "Expect error")
+	assert.Equal(t, parent, SpanFromContext(ctx), "Expect span in context")
+}
+
+func TestNoopTracerPropagate(t *testing.T) {
+	remote, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextWithRemoteSpanContext(context.Background(), remote)
+
+	_, span := Start(ctx, "noop")
+	assert.Equal(t, remote, span.SpanContext(), "Expect remote span context kept")
+}
@@ -0,0 +1,210 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	phttp "github.com/agung-project/golib/http"
+)
+
+type Placeholder int
+
+const (
+	// PlaceholderDollar is PostgreSQL placeholder style ($1, $2, ...)
+	PlaceholderDollar Placeholder = iota
+	// PlaceholderQuestion is MySQL placeholder style (?)
+	PlaceholderQuestion
+)
+
+var (
+	ErrInvalidColumn   = errors.New("invalid column name")
+	ErrInvalidOperator = errors.New("invalid filter operator")
+	ErrInvalidValues   = errors.New("invalid filter values")
+	// ErrCursorNotSupported is returned by Build for cursor pagination (After/Before is set), build the keyset
+	// condition with Arg and use Where, OrderBy and LimitOffset instead
+	ErrCursorNotSupported = errors.New("cursor pagination is not supported by Build")
+)
+
+var columnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var comparisonOperators = map[phttp.FilterOperator]string{
+	phttp.FilterEq:  "=",
+	phttp.FilterNe:  "<>",
+	phttp.FilterGt:  ">",
+	phttp.FilterGte: ">=",
+	phttp.FilterLt:  "<",
+	phttp.FilterLte: "<=",
+}
+
+// likeEscape is the LIKE escape character, "!" is used instead of backslash because backslash in string literal
+// is an escape in MySQL but not in PostgreSQL and SQLite
+const likeEscape = "!"
+
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, `%`, likeEscape+`%`, `_`, likeEscape+`_`)
+
+// QueryBuilder build parameterized WHERE, ORDER BY, LIMIT and OFFSET fragments from parsed filters, sorts and pagination.
+// Values are always passed as args and column names must be plain identifiers (from FilterSchema/SortAllowlist),
+// use one builder per query so the placeholder numbers continue across fragments
+type QueryBuilder struct {
+	Placeholder Placeholder
+	args        []interface{}
+}
+
+// NewQueryBuilder create builder, args are the existing args of the query (e.g. tenant id as $1)
+func NewQueryBuilder(placeholder Placeholder, args ...interface{}) *QueryBuilder {
+	return &QueryBuilder{Placeholder: placeholder, args: args}
+}
+
+// Arg add value to args and return its placeholder, use it for custom condition
+func (b *QueryBuilder) Arg(value interface{}) string {
+	b.args = append(b.args, value)
+
+	if b.Placeholder == PlaceholderQuestion {
+		return "?"
+	}
+
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// Args return all args in placeholder order
+func (b *QueryBuilder) Args() []interface{} {
+	return b.args
+}
+
+// Where return "WHERE cond AND cond", empty string when there is no filter
+func (b *QueryBuilder) Where(filters []phttp.Filter) (string, error) {
+	conditions, err := b.Conditions(filters)
+	if err != nil || len(conditions) == 0 {
+		return "", err
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), nil
+}
+
+// Conditions return condition of each filter, use it to combine with custom conditions
+func (b *QueryBuilder) Conditions(filters []phttp.Filter) ([]string, error) {
+	conditions := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		condition, err := b.condition(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
+
+func (b *QueryBuilder) condition(filter phttp.Filter) (string, error) {
+	if !columnPattern.MatchString(filter.Field) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidColumn, filter.Field)
+	}
+
+	if op, ok := comparisonOperators[filter.Operator]; ok {
+		if len(filter.Values) != 1 {
+			return "", fmt.Errorf("%w: %s need 1 value", ErrInvalidValues, filter.Operator)
+		}
+
+		return filter.Field + " " + op + " " + b.Arg(filter.Values[0]), nil
+	}
+
+	switch filter.Operator {
+	case phttp.FilterLike:
+		if len(filter.Values) != 1 {
+			return "", fmt.Errorf("%w: %s need 1 value", ErrInvalidValues, filter.Operator)
+		}
+
+		value := "%" + likeEscaper.Replace(fmt.Sprint(filter.Values[0])) + "%"
+		return filter.Field + " LIKE " + b.Arg(value) + " ESCAPE '" + likeEscape + "'", nil
+	case phttp.FilterIn, phttp.FilterNotIn:
+		if len(filter.Values) == 0 {
+			return "", fmt.Errorf("%w: %s need at least 1 value", ErrInvalidValues, filter.Operator)
+		}
+
+		placeholders := make([]string, len(filter.Values))
+		for i, value := range filter.Values {
+			placeholders[i] = b.Arg(value)
+		}
+
+		op := " IN ("
+		if filter.Operator == phttp.FilterNotIn {
+			op = " NOT IN ("
+		}
+
+		return filter.Field + op + strings.Join(placeholders, ", ") + ")", nil
+	case phttp.FilterBetween:
+		if len(filter.Values) != 2 {
+			return "", fmt.Errorf("%w: %s need 2 values", ErrInvalidValues, filter.Operator)
+		}
+
+		return filter.Field + " BETWEEN " + b.Arg(filter.Values[0]) + " AND " + b.Arg(filter.Values[1]), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidOperator, filter.Operator)
+}
+
+// OrderBy return "ORDER BY col DESC, col ASC", empty string when there is no sort
+func (b *QueryBuilder) OrderBy(sorts []phttp.SortField) (string, error) {
+	if len(sorts) == 0 {
+		return "", nil
+	}
+
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		if !columnPattern.MatchString(sort.Name) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidColumn, sort.Name)
+		}
+
+		parts[i] = sort.Name + " ASC"
+		if sort.Desc {
+			parts[i] = sort.Name + " DESC"
+		}
+	}
+
+	return "ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// LimitOffset return "LIMIT $n OFFSET $n" from the request pagination, empty string when limit is not set
+func (b *QueryBuilder) LimitOffset(reqPage phttp.RequestPagination) string {
+	if reqPage.Limit <= 0 {
+		return ""
+	}
+
+	clause := "LIMIT " + b.Arg(reqPage.Limit)
+	if reqPage.Offset > 0 {
+		clause += " OFFSET " + b.Arg(reqPage.Offset)
+	}
+
+	return clause
+}
+
+// Build return WHERE, ORDER BY, LIMIT and OFFSET fragment to be appended after FROM, the args are in Args().
+// It return ErrCursorNotSupported when the request pagination is cursor pagination
+func (b *QueryBuilder) Build(reqPage phttp.RequestPagination, filters []phttp.Filter, sorts []phttp.SortField) (string, error) {
+	if reqPage.After != "" || reqPage.Before != "" {
+		return "", ErrCursorNotSupported
+	}
+
+	where, err := b.Where(filters)
+	if err != nil {
+		return "", err
+	}
+
+	orderBy, err := b.OrderBy(sorts)
+	if err != nil {
+		return "", err
+	}
+
+	clauses := []string{}
+	for _, clause := range []string{where, orderBy, b.LimitOffset(reqPage)} {
+		if clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+
+	return strings.Join(clauses, " "), nil
+}
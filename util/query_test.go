@@ -0,0 +1,110 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	phttp "github.com/agung-project/golib/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilderOperators(t *testing.T) {
+	date1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	date2 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   phttp.Filter
+		postgres string
+		mysql    string
+		args     []interface{}
+	}{
+		{name: "eq", filter: phttp.Filter{Field: "status", Operator: phttp.FilterEq, Values: []interface{}{"active"}}, postgres: "status = $1", mysql: "status = ?", args: []interface{}{"active"}},
+		{name: "ne", filter: phttp.Filter{Field: "status", Operator: phttp.FilterNe, Values: []interface{}{"active"}}, postgres: "status <> $1", mysql: "status <> ?", args: []interface{}{"active"}},
+		{name: "gt", filter: phttp.Filter{Field: "amount", Operator: phttp.FilterGt, Values: []interface{}{int64(10)}}, postgres: "amount > $1", mysql: "amount > ?", args: []interface{}{int64(10)}},
+		{name: "gte", filter: phttp.Filter{Field: "amount", Operator: phttp.FilterGte, Values: []interface{}{int64(10)}}, postgres: "amount >= $1", mysql: "amount >= ?", args: []interface{}{int64(10)}},
+		{name: "lt", filter: phttp.Filter{Field: "amount", Operator: phttp.FilterLt, Values: []interface{}{int64(10)}}, postgres: "amount < $1", mysql: "amount < ?", args: []interface{}{int64(10)}},
+		{name: "lte", filter: phttp.Filter{Field: "amount", Operator: phttp.FilterLte, Values: []interface{}{int64(10)}}, postgres: "amount <= $1", mysql: "amount <= ?", args: []interface{}{int64(10)}},
+		{name: "like", filter: phttp.Filter{Field: "name", Operator: phttp.FilterLike, Values: []interface{}{"50%_off"}}, postgres: "name LIKE $1 ESCAPE '!'", mysql: "name LIKE ? ESCAPE '!'", args: []interface{}{"%50!%!_off%"}},
+		{name: "in", filter: phttp.Filter{Field: "tag", Operator: phttp.FilterIn, Values: []interface{}{"a", "b"}}, postgres: "tag IN ($1, $2)", mysql: "tag IN (?, ?)", args: []interface{}{"a", "b"}},
+		{name: "nin", filter: phttp.Filter{Field: "tag", Operator: phttp.FilterNotIn, Values: []interface{}{"a"}}, postgres: "tag NOT IN ($1)", mysql: "tag NOT IN (?)", args: []interface{}{"a"}},
+		{name: "between", filter: phttp.Filter{Field: "o.created_at", Operator: phttp.FilterBetween, Values: []interface{}{date1, date2}}, postgres: "o.created_at BETWEEN $1 AND $2", mysql: "o.created_at BETWEEN ? AND ?", args: []interface{}{date1, date2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewQueryBuilder(PlaceholderDollar)
+			where, err := b.Where([]phttp.Filter{tt.filter})
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, "WHERE "+tt.postgres, where, "Expect PostgreSQL condition")
+			assert.Equal(t, tt.args, b.Args(), "Expect args")
+
+			b = NewQueryBuilder(PlaceholderQuestion)
+			where, err = b.Where([]phttp.Filter{tt.filter})
+			assert.Nil(t, err, "Expect no error")
+			assert.Equal(t, "WHERE "+tt.mysql, where, "Expect MySQL condition")
+			assert.Equal(t, tt.args, b.Args(), "Expect args")
+		})
+	}
+}
+
+func TestQueryBuilderInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter phttp.Filter
+		err    error
+	}{
+		{name: "injection in column", filter: phttp.Filter{Field: "status; DROP TABLE users", Operator: phttp.FilterEq, Values: []interface{}{"a"}}, err: ErrInvalidColumn},
+		{name: "quoted column", filter: phttp.Filter{Field: `"status"`, Operator: phttp.FilterEq, Values: []interface{}{"a"}}, err: ErrInvalidColumn},
+		{name: "unknown operator", filter: phttp.Filter{Field: "status", Operator: "regex", Values: []interface{}{"a"}}, err: ErrInvalidOperator},
+		{name: "eq without value", filter: phttp.Filter{Field: "status", Operator: phttp.FilterEq}, err: ErrInvalidValues},
+		{name: "in without value", filter: phttp.Filter{Field: "status", Operator: phttp.FilterIn}, err: ErrInvalidValues},
+		{name: "between with 1 value", filter: phttp.Filter{Field: "amount", Operator: phttp.FilterBetween, Values: []interface{}{1}}, err: ErrInvalidValues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewQueryBuilder(PlaceholderDollar).Where([]phttp.Filter{tt.filter})
+			assert.True(t, errors.Is(err, tt.err), "Expect %v, got %v", tt.err, err)
+		})
+	}
+
+	_, err := NewQueryBuilder(PlaceholderDollar).OrderBy([]phttp.SortField{{Name: "name DESC; --"}})
+	assert.True(t, errors.Is(err, ErrInvalidColumn), "Expect invalid sort column")
+}
+
+func TestQueryBuilderBuild(t *testing.T) {
+	filters := []phttp.Filter{
+		{Field: "o.status", Operator: phttp.FilterIn, Values: []interface{}{"paid", "shipped"}},
+		{Field: "o.amount", Operator: phttp.FilterGte, Values: []interface{}{int64(1000)}},
+	}
+	sorts := []phttp.SortField{{Name: "o.created_at", Desc: true}, {Name: "o.id"}}
+	reqPage := phttp.RequestPagination{Limit: 10, Offset: 20}
+
+	b := NewQueryBuilder(PlaceholderDollar, "tenant-1")
+	conditions, err := b.Conditions(filters)
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, []string{"o.status IN ($2, $3)", "o.amount >= $4"}, conditions, "Expect placeholder continue from existing args")
+
+	b = NewQueryBuilder(PlaceholderDollar)
+	clause, err := b.Build(reqPage, filters, sorts)
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, "WHERE o.status IN ($1, $2) AND o.amount >= $3 ORDER BY o.created_at DESC, o.id ASC LIMIT $4 OFFSET $5", clause, "Expect PostgreSQL clause")
+	assert.Equal(t, []interface{}{"paid", "shipped", int64(1000), 10, 20}, b.Args(), "Expect args")
+
+	b = NewQueryBuilder(PlaceholderQuestion)
+	clause, err = b.Build(reqPage, filters, sorts)
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, "WHERE o.status IN (?, ?) AND o.amount >= ? ORDER BY o.created_at DESC, o.id ASC LIMIT ? OFFSET ?", clause, "Expect MySQL clause")
+
+	b = NewQueryBuilder(PlaceholderQuestion)
+	clause, err = b.Build(phttp.RequestPagination{Limit: 10}, nil, nil)
+	assert.Nil(t, err, "Expect no error")
+	assert.Equal(t, "LIMIT ?", clause, "Expect only limit")
+	assert.Equal(t, []interface{}{10}, b.Args(), "Expect limit arg")
+
+	b = NewQueryBuilder(PlaceholderQuestion)
+	_, err = b.Build(phttp.RequestPagination{Limit: 10, After: "cursor"}, nil, nil)
+	assert.True(t, errors.Is(err, ErrCursorNotSupported), "Expect cursor not supported")
+}